@@ -3,17 +3,15 @@ package app
 import (
 	"context"
 	"fmt"
-	"net/http"
 	"os"
-	"sync"
 	"time"
 
 	"github.com/lameaux/bro/internal/client/config"
+	"github.com/lameaux/bro/internal/client/engine"
+	"github.com/lameaux/bro/internal/client/live"
+	"github.com/lameaux/bro/internal/client/report"
 	"github.com/lameaux/bro/internal/client/runner"
 	"github.com/lameaux/bro/internal/client/stats"
-	"github.com/lameaux/bro/internal/client/thresholds"
-	"github.com/lameaux/bro/internal/shared/httpclient"
-	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
 
@@ -22,6 +20,10 @@ const (
 	exitError   = 1
 
 	outputFilePermissions = 0o640
+
+	formatTXT  = "txt"
+	formatCSV  = "csv"
+	formatJSON = "json"
 )
 
 type App struct {
@@ -30,6 +32,8 @@ type App struct {
 	conf        *config.Config
 	flags       *Flags
 	statsSender *stats.Sender
+	dashboard   *live.Dashboard
+	engine      *engine.Engine
 }
 
 func New(name, version, buildHash, buildDate string) (*App, error) {
@@ -44,6 +48,10 @@ func New(name, version, buildHash, buildDate string) (*App, error) {
 	application.setupLog()
 	application.printAbout()
 
+	if application.command() == commandCompare {
+		return application, nil
+	}
+
 	if err := application.loadConfig(); err != nil {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
@@ -57,118 +65,112 @@ func New(name, version, buildHash, buildDate string) (*App, error) {
 		application.statsSender = w
 	}
 
-	return application, nil
-}
-
-func (a *App) Run(ctx context.Context) int {
-	if a.statsSender != nil {
-		go a.statsSender.Run(ctx)
+	if application.flags.Live {
+		application.setupDashboard()
 	}
 
-	results := a.runScenarios(ctx)
-
-	success := a.processResults(results)
-
-	if !success && !a.flags.SkipExitCode {
-		return exitError
+	if err := application.setupEngine(); err != nil {
+		return nil, err
 	}
 
-	return exitSuccess
+	return application, nil
 }
 
-func (a *App) runScenarios(ctx context.Context) *stats.Stats {
-	results := stats.New()
-	defer results.StopTimer()
+func (a *App) setupEngine() error {
+	var listeners []runner.StatListener
 
-	httpClient := httpclient.New(a.conf.HTTPClient)
+	if a.statsSender != nil {
+		listeners = append(listeners, a.statsSender)
+	}
 
-	log.Info().
-		Bool("parallel", a.conf.Parallel).
-		Msg("executing scenarios... press Ctrl+C (SIGINT) or send SIGTERM to terminate.")
+	if a.dashboard != nil {
+		listeners = append(listeners, a.dashboard)
+	}
 
-	if a.conf.Parallel {
-		a.runParallel(ctx, httpClient, results)
-	} else {
-		a.runSerial(ctx, httpClient, results)
+	e, err := engine.New(a.conf, listeners)
+	if err != nil {
+		return err //nolint:wrapcheck
 	}
 
-	return results
-}
+	a.engine = e
 
-func (a *App) runParallel(
-	ctx context.Context,
-	httpClient *http.Client,
-	results *stats.Stats,
-) {
-	var wg sync.WaitGroup
+	return nil
+}
 
-	wg.Add(len(a.conf.Scenarios))
+func (a *App) Run(ctx context.Context) int {
+	if a.command() == commandCompare {
+		return a.runCompare()
+	}
 
-	for scenarioID, scenario := range a.conf.Scenarios {
-		go func(scenarioID int, scenario *config.Scenario) {
-			defer wg.Done()
-			a.runScenario(ctx, httpClient, scenarioID, scenario, results)
-		}(scenarioID, scenario)
+	if a.statsSender != nil {
+		go a.statsSender.Run(ctx)
 	}
 
-	wg.Wait()
-}
+	dashboardCtx, stopDashboard := context.WithCancel(ctx)
 
-func (a *App) runSerial(
-	ctx context.Context,
-	httpClient *http.Client,
-	results *stats.Stats,
-) {
-	for scenarioID, scenario := range a.conf.Scenarios {
-		a.runScenario(ctx, httpClient, scenarioID, scenario, results)
+	if a.dashboard != nil {
+		go a.dashboard.Run(dashboardCtx)
 	}
-}
 
-func (a *App) runScenario(
-	ctx context.Context,
-	httpClient *http.Client,
-	scenarioID int,
-	scenario *config.Scenario,
-	results *stats.Stats,
-) {
-	localCounters := stats.NewCounters()
+	log.Info().
+		Bool("parallel", a.conf.Parallel).
+		Msg("executing scenarios... press Ctrl+C (SIGINT) or send SIGTERM to terminate.")
 
-	listeners := []runner.StatListener{localCounters}
+	// failed scenarios are logged by engine and reported as missing stats
+	results, runErr := a.engine.Run(ctx)
 
-	if a.statsSender != nil {
-		listeners = append(listeners, a.statsSender)
+	if err := a.engine.Close(); err != nil {
+		log.Warn().Err(err).Msg("failed to close http clients")
 	}
 
-	r := runner.New(httpClient, scenarioID, scenario, listeners)
+	stopDashboard()
 
-	startTime := time.Now()
+	if a.dashboard != nil {
+		a.dashboard.Stop()
+	}
 
-	err := r.Run(ctx)
-	if err != nil {
-		log.Error().Err(err).
-			Dict("scenario", zerolog.Dict().Str("name", scenario.Name)).
-			Msg("failed to run scenario")
+	success := a.processResults(results, runErr)
 
-		return
+	if !success && !a.flags.SkipExitCode {
+		return exitError
 	}
 
-	results.SetCounters(scenario.Name, localCounters)
-	results.SetDuration(scenario.Name, time.Since(startTime).Round(time.Millisecond))
+	return exitSuccess
+}
 
-	passed, err := thresholds.ValidateScenario(scenario, localCounters)
-	if err != nil {
-		log.Warn().
-			Dict("scenario", zerolog.Dict().Str("name", scenario.Name)).
-			Msg("failed to validate thresholds")
+// command returns the first argument if it is a command, a config file with the same name takes precedence.
+func (a *App) command() string {
+	if len(a.flags.Args) == 0 || a.flags.URL {
+		return ""
+	}
 
-		return
+	if _, err := os.Stat(a.flags.Args[0]); err == nil {
+		return ""
 	}
 
-	results.SetThresholdsPassed(scenario.Name, passed)
+	return a.flags.Args[0]
 }
 
-func (a *App) processResults(runStats *stats.Stats) bool {
-	success := runStats.AllThresholdsPassed()
+// processResults prints a report, the run fails if thresholds failed or any scenario failed to run.
+func (a *App) processResults(runStats *stats.Stats, runErr error) bool {
+	rep := report.New(a.conf, runStats, runStats.AllThresholdsPassed())
+
+	success := rep.Success && runErr == nil && len(rep.Missing) == 0
+	rep.Success = success
+
+	if a.flags.Baseline != "" {
+		comparison, err := a.compareWithBaseline(rep)
+		if err != nil {
+			log.Error().Err(err).Str("baseline", a.flags.Baseline).Msg("failed to compare with baseline")
+
+			success = false
+			rep.Success = false
+		} else {
+			rep.Comparison = comparison
+			success = success && comparison.Passed
+			rep.Success = success
+		}
+	}
 
 	log.Info().
 		Str("totalDuration", runStats.TotalDuration().Round(time.Millisecond).String()).
@@ -180,24 +182,30 @@ func (a *App) processResults(runStats *stats.Stats) bool {
 	var formattedOutput string
 
 	switch a.flags.Format {
-	case "txt":
-		formattedOutput = generateTXT(a.conf, runStats, success)
-	case "csv":
-		formattedOutput = generateCSV(a.conf, runStats)
+	case formatTXT:
+		formattedOutput = generateTXT(rep)
+	case formatCSV:
+		formattedOutput = generateCSV(rep)
+	case formatJSON:
+		formattedOutput = generateJSON(rep)
 	default:
 		log.Error().Str("format", a.flags.Format).Msg("invalid format")
 	}
 
+	a.writeOutput(formattedOutput)
+
+	return success
+}
+
+func (a *App) writeOutput(formattedOutput string) {
 	if a.flags.Output == "stdout" {
 		fmt.Println(formattedOutput) //nolint:forbidigo
 
-		return success
+		return
 	}
 
 	err := os.WriteFile(a.flags.Output, []byte(formattedOutput), outputFilePermissions)
 	if err != nil {
 		log.Error().Str("output", a.flags.Output).Err(err).Msg("failed to print output")
 	}
-
-	return success
 }
@@ -0,0 +1,116 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/lameaux/bro/internal/client/report"
+	"github.com/lameaux/bro/internal/client/stats"
+)
+
+func TestProcessResults_MissingScenario(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		runErr error
+	}{
+		{name: "missing stats"},
+		{name: "run error", runErr: errors.New("failed to run scenario")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			output := filepath.Join(t.TempDir(), "report.json")
+
+			a := &App{
+				conf: &config.Config{
+					Name:      "test",
+					Scenarios: []*config.Scenario{{Name: "unknown", TypeRaw: "unknown"}},
+				},
+				flags: &Flags{Format: formatJSON, Output: output},
+			}
+
+			runStats := stats.New()
+			runStats.StopTimer()
+
+			if a.processResults(runStats, tt.runErr) {
+				t.Fatal("expected run to fail")
+			}
+
+			data, err := os.ReadFile(output)
+			if err != nil {
+				t.Fatalf("failed to read report: %v", err)
+			}
+
+			var rep report.Report
+			if err := json.Unmarshal(data, &rep); err != nil {
+				t.Fatalf("failed to parse report: %v", err)
+			}
+
+			if rep.Success {
+				t.Error("expected report to fail")
+			}
+
+			if len(rep.Missing) != 1 || rep.Missing[0] != "unknown" {
+				t.Errorf("unexpected missing scenarios: %v", rep.Missing)
+			}
+		})
+	}
+}
+
+func TestProcessResults_InvalidBaseline(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	a := &App{
+		conf: &config.Config{Name: "test"},
+		flags: &Flags{
+			Format:   formatJSON,
+			Output:   filepath.Join(dir, "report.json"),
+			Baseline: filepath.Join(dir, "missing.json"),
+		},
+	}
+
+	runStats := stats.New()
+	runStats.StopTimer()
+
+	if a.processResults(runStats, nil) {
+		t.Error("expected run to fail when baseline can not be loaded")
+	}
+}
+
+func TestCompareRules(t *testing.T) {
+	t.Parallel()
+
+	maxIncrease := config.Percent(0.2)
+
+	a := &App{flags: &Flags{MaxIncrease: "10%"}}
+
+	rulesFor, err := a.compareRules(&config.Config{
+		Scenarios: []*config.Scenario{
+			{
+				Name:       "custom",
+				Thresholds: []*config.Threshold{{Metric: "rps", MaxIncrease: &maxIncrease}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if rules := rulesFor("custom"); len(rules) != 1 || rules[0].Metric != "rps" || rules[0].MaxIncrease != maxIncrease {
+		t.Errorf("unexpected rules of scenario: %+v", rules)
+	}
+
+	if rules := rulesFor("other"); len(rules) != 1 || rules[0].Metric != defaultCompareMetric {
+		t.Errorf("unexpected default rules: %+v", rules)
+	}
+}
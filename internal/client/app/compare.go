@@ -0,0 +1,200 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/lameaux/bro/internal/client/report"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	commandCompare = "compare"
+
+	// metric used for relative thresholds when a scenario does not define its own.
+	defaultCompareMetric = "latency.p95"
+)
+
+var errMissingReports = errors.New("missing reports")
+
+func (a *App) runCompare() int {
+	args := a.flags.Args
+	if len(args) < 3 { //nolint:mnd
+		log.Error().
+			Err(errMissingReports).
+			Msgf("Example: %s [flags] compare <baseline.json> <current.json> [config.yaml]", a.name)
+
+		return exitError
+	}
+
+	baseline, err := report.Load(args[1])
+	if err != nil {
+		log.Error().Err(err).Str("file", args[1]).Msg("failed to load baseline")
+
+		return exitError
+	}
+
+	current, err := report.Load(args[2])
+	if err != nil {
+		log.Error().Err(err).Str("file", args[2]).Msg("failed to load results")
+
+		return exitError
+	}
+
+	// relative thresholds of scenarios are taken from config, if it is given
+	conf := &config.Config{}
+
+	if len(args) > 3 { //nolint:mnd
+		conf, err = config.Load(args[3])
+		if err != nil {
+			log.Error().Err(err).Str("file", args[3]).Msg("failed to load config")
+
+			return exitError
+		}
+	}
+
+	rulesFor, err := a.compareRules(conf)
+	if err != nil {
+		log.Error().Err(err).Msg("invalid maxIncrease")
+
+		return exitError
+	}
+
+	comparison := report.Compare(baseline, current, rulesFor)
+	comparison.Baseline = args[1]
+
+	log.Info().
+		Str("baseline", args[1]).
+		Str("current", args[2]).
+		Bool("passed", comparison.Passed).
+		Msg("comparison")
+
+	a.writeOutput(formatComparison(comparison, a.flags.Format))
+
+	if !comparison.Passed && !a.flags.SkipExitCode {
+		return exitError
+	}
+
+	return exitSuccess
+}
+
+func (a *App) compareWithBaseline(rep *report.Report) (*report.Comparison, error) {
+	baseline, err := report.Load(a.flags.Baseline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load baseline: %w", err)
+	}
+
+	rulesFor, err := a.compareRules(a.conf)
+	if err != nil {
+		return nil, fmt.Errorf("invalid maxIncrease: %w", err)
+	}
+
+	comparison := report.Compare(baseline, rep, rulesFor)
+	comparison.Baseline = a.flags.Baseline
+
+	return comparison, nil
+}
+
+// compareRules returns relative thresholds of config scenarios, --maxIncrease is used for scenarios without them.
+func (a *App) compareRules(conf *config.Config) (func(scenario string) []report.Rule, error) {
+	defaultRules, err := a.defaultCompareRules()
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make(map[string][]report.Rule, len(conf.Scenarios))
+	for _, scenario := range conf.Scenarios {
+		rules[scenario.Name] = report.RulesFromThresholds(scenario.Thresholds)
+	}
+
+	return func(scenarioName string) []report.Rule {
+		if scenarioRules := rules[scenarioName]; len(scenarioRules) > 0 {
+			return scenarioRules
+		}
+
+		return defaultRules
+	}, nil
+}
+
+func (a *App) defaultCompareRules() ([]report.Rule, error) {
+	if a.flags.MaxIncrease == "" {
+		return nil, nil
+	}
+
+	maxIncrease, err := config.ParsePercent(a.flags.MaxIncrease)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse percent: %w", err)
+	}
+
+	return []report.Rule{{Metric: defaultCompareMetric, MaxIncrease: maxIncrease}}, nil
+}
+
+func formatComparison(comparison *report.Comparison, format string) string {
+	switch format {
+	case formatCSV:
+		return generateComparisonTable(comparison).RenderCSV()
+	case formatJSON:
+		return generateJSON(comparison)
+	default:
+		var output strings.Builder
+
+		output.WriteString(fmt.Sprintf("Baseline: %s\n", comparison.Baseline))
+		output.WriteString(generateComparisonTable(comparison).Render())
+
+		if comparison.Passed {
+			output.WriteString("\nOK\n")
+		} else {
+			output.WriteString("\nFailed\n")
+		}
+
+		return output.String()
+	}
+}
+
+func generateComparisonTable(comparison *report.Comparison) table.Writer { //nolint: ireturn
+	tableWriter := table.NewWriter()
+	tableWriter.AppendHeader(table.Row{
+		"Scenario", "Metric", "Baseline", "Current", "Change", "Max Increase", "Passed",
+	})
+
+	for _, diff := range comparison.Diffs {
+		maxIncrease := ""
+		if diff.MaxIncrease != nil {
+			maxIncrease = diff.MaxIncrease.String()
+		}
+
+		tableWriter.AppendRow(table.Row{
+			diff.Scenario,
+			diff.Metric,
+			formatFloat(diff.Baseline),
+			formatFloat(diff.Current),
+			formatChange(diff),
+			maxIncrease,
+			diff.Passed,
+		})
+	}
+
+	tableWriter.SetStyle(table.StyleLight)
+
+	return tableWriter
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func formatChange(diff *report.Diff) string {
+	if diff.Error != "" {
+		return diff.Error
+	}
+
+	if diff.Change == nil {
+		return "n/a"
+	}
+
+	return fmt.Sprintf("%+.1f%%", *diff.Change*100) //nolint:mnd
+}
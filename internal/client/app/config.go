@@ -40,6 +40,9 @@ func (a *App) makeConfigFromFlags() (*config.Config, error) {
 		Name: "Calling " + url,
 		HTTPClient: config.HTTPClient{
 			Timeout: a.flags.Timeout,
+			Proxy: config.Proxy{
+				URL: a.flags.Proxy,
+			},
 		},
 		Scenarios: []*config.Scenario{
 			{
@@ -48,8 +51,9 @@ func (a *App) makeConfigFromFlags() (*config.Config, error) {
 				DurationRaw: a.flags.Duration,
 				ThreadsRaw:  a.flags.Threads,
 				HTTPRequest: config.HTTPRequest{
-					URL:       url,
-					MethodRaw: a.flags.Method,
+					URL:        url,
+					MethodRaw:  a.flags.Method,
+					UnixSocket: a.flags.UnixSocket,
 				},
 			},
 		},
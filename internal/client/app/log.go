@@ -3,6 +3,8 @@ package app
 import (
 	"os"
 
+	"github.com/lameaux/bro/internal/client/live"
+
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
@@ -21,3 +23,12 @@ func (a *App) setupLog() {
 		log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
 	}
 }
+
+func (a *App) setupDashboard() {
+	a.dashboard = live.New(os.Stderr)
+
+	// logs would break the dashboard rendered on terminal
+	if a.dashboard.IsTerminal() && !a.flags.Debug {
+		zerolog.SetGlobalLevel(max(zerolog.GlobalLevel(), zerolog.WarnLevel))
+	}
+}
@@ -1,47 +1,253 @@
 package app
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/jedib0t/go-pretty/v6/table"
-	"github.com/lameaux/bro/internal/client/config"
-	"github.com/lameaux/bro/internal/client/stats"
-	"github.com/rs/zerolog"
+	"github.com/lameaux/bro/internal/client/report"
+	"github.com/lameaux/bro/internal/client/tracking"
 	"github.com/rs/zerolog/log"
 )
 
 const (
-	latencyPercentile = 99
+	latencyPercentileKey = "p99"
+	medianPercentileKey  = "p50"
 )
 
-func generateTable(conf *config.Config, results *stats.Stats) table.Writer { //nolint: ireturn
+func generateTable(rep *report.Report) table.Writer { //nolint: ireturn
 	tableWriter := table.NewWriter()
 	tableWriter.AppendHeader(table.Row{
 		"Scenario", "Total", "Success", "Failed", "Timeout", "Invalid", "Latency @P99", "Duration", "RPS", "Passed",
 	})
 
-	for _, scenarioName := range conf.ScenarioNames() {
-		counters := results.Counters(scenarioName)
-		if counters == nil {
-			log.Warn().
-				Dict("scenario", zerolog.Dict().Str("name", scenarioName)).
-				Msg("missing stats")
+	for _, scenario := range rep.Scenarios {
+		tableWriter.AppendRow(table.Row{
+			scenario.Name,
+			scenario.Total,
+			scenario.Success,
+			scenario.Failed,
+			scenario.Timeout,
+			scenario.Invalid,
+			fmt.Sprintf("%d ms", scenario.LatencyMs[latencyPercentileKey]),
+			scenario.Duration(),
+			scenario.RPS,
+			scenario.Passed,
+		})
+	}
+
+	tableWriter.SetStyle(table.StyleLight)
+
+	return tableWriter
+}
+
+func generateTimingsTable(rep *report.Report) table.Writer { //nolint: ireturn
+	tableWriter := table.NewWriter()
+	tableWriter.AppendHeader(table.Row{
+		"Scenario", "DNS @P99", "Connect @P99", "TLS @P99", "TTFB @P99", "Download @P99", "Connections", "Reused",
+		"TLS Handshakes", "TLS Resumed",
+	})
+
+	for _, scenario := range rep.Scenarios {
+		row := table.Row{scenario.Name}
+
+		for _, phase := range tracking.Phases {
+			row = append(row, fmt.Sprintf("%.3f ms", scenario.TimingsMs[phase][latencyPercentileKey]))
+		}
+
+		row = append(row, scenario.Connections, scenario.Reused, scenario.TLS.Handshakes, scenario.TLS.Resumed)
+
+		tableWriter.AppendRow(row)
+	}
+
+	tableWriter.SetStyle(table.StyleLight)
+
+	return tableWriter
+}
+
+func generateErrorsTable(rep *report.Report) table.Writer { //nolint: ireturn
+	tableWriter := table.NewWriter()
+	tableWriter.AppendHeader(table.Row{"Scenario", "Category", "Count"})
+
+	for _, scenario := range rep.Scenarios {
+		categories := make([]string, 0, len(scenario.Errors))
+		for category := range scenario.Errors {
+			categories = append(categories, category)
+		}
+
+		sort.Strings(categories)
+
+		for _, category := range categories {
+			tableWriter.AppendRow(table.Row{scenario.Name, category, scenario.Errors[category]})
+		}
+	}
+
+	tableWriter.SetStyle(table.StyleLight)
+
+	return tableWriter
+}
+
+func generateErrorSamplesTable(rep *report.Report) table.Writer { //nolint: ireturn
+	tableWriter := table.NewWriter()
+	tableWriter.AppendHeader(table.Row{"Scenario", "Error", "Count"})
+
+	for _, scenario := range rep.Scenarios {
+		for _, sample := range scenario.ErrorSamples {
+			tableWriter.AppendRow(table.Row{scenario.Name, sample.Message, sample.Count})
+		}
+	}
+
+	tableWriter.SetStyle(table.StyleLight)
+
+	return tableWriter
+}
+
+func generateTrafficTable(rep *report.Report) table.Writer { //nolint: ireturn
+	tableWriter := table.NewWriter()
+	tableWriter.AppendHeader(table.Row{
+		"Scenario", "Sent", "Received", "Received (decoded)", "Sent MB/s", "Received MB/s", "Protocols",
+	})
+
+	for _, scenario := range rep.Scenarios {
+		tableWriter.AppendRow(table.Row{
+			scenario.Name,
+			formatBytes(scenario.Bytes.Sent),
+			formatBytes(scenario.Bytes.Received),
+			formatBytes(scenario.Bytes.ReceivedDecoded),
+			fmt.Sprintf("%.3f", scenario.Throughput.Sent),
+			fmt.Sprintf("%.3f", scenario.Throughput.Received),
+			formatCounts(scenario.Protocols),
+		})
+	}
+
+	tableWriter.SetStyle(table.StyleLight)
+
+	return tableWriter
+}
+
+func formatBytes(bytes int64) string {
+	const unit = 1000
+
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "kMGTPE"[exp])
+}
+
+func generateCodesTable(rep *report.Report) table.Writer { //nolint: ireturn
+	tableWriter := table.NewWriter()
+	tableWriter.AppendHeader(table.Row{"Scenario", "Code", "Total", "Latency @P99"})
+
+	for _, scenario := range rep.Scenarios {
+		for _, code := range scenario.Codes {
+			tableWriter.AppendRow(table.Row{
+				scenario.Name,
+				code.Name,
+				code.Total,
+				fmt.Sprintf("%d ms", code.LatencyMs[latencyPercentileKey]),
+			})
+		}
+	}
+
+	tableWriter.SetStyle(table.StyleLight)
+
+	return tableWriter
+}
+
+func generateStagesTable(rep *report.Report) table.Writer { //nolint: ireturn
+	tableWriter := table.NewWriter()
+	tableWriter.AppendHeader(table.Row{"Scenario", "Stage", "Total", "Success", "Failed", "Codes", "Latency @P99"})
+
+	for _, scenario := range rep.Scenarios {
+		for _, stage := range scenario.Stages {
+			tableWriter.AppendRow(table.Row{
+				scenario.Name,
+				stage.Name,
+				stage.Total,
+				stage.Success,
+				stage.Failed,
+				formatCounts(stage.Codes),
+				fmt.Sprintf("%d ms", stage.LatencyMs[latencyPercentileKey]),
+			})
+		}
+	}
+
+	tableWriter.SetStyle(table.StyleLight)
+
+	return tableWriter
+}
+
+func generateClosedTable(rep *report.Report) table.Writer { //nolint: ireturn
+	tableWriter := table.NewWriter()
+	tableWriter.AppendHeader(table.Row{"Scenario", "Closed", "Close codes", "Lifetime @P50", "Lifetime @P99"})
+
+	for _, scenario := range rep.Scenarios {
+		if scenario.Closed == nil {
+			continue
+		}
 
+		tableWriter.AppendRow(table.Row{
+			scenario.Name,
+			scenario.Closed.Total,
+			formatCounts(scenario.Closed.Codes),
+			fmt.Sprintf("%d ms", scenario.Closed.LifetimeMs[medianPercentileKey]),
+			fmt.Sprintf("%d ms", scenario.Closed.LifetimeMs[latencyPercentileKey]),
+		})
+	}
+
+	tableWriter.SetStyle(table.StyleLight)
+
+	return tableWriter
+}
+
+func generateStreamsTable(rep *report.Report) table.Writer { //nolint: ireturn
+	tableWriter := table.NewWriter()
+	tableWriter.AppendHeader(table.Row{
+		"Scenario", "Streams", "Events", "First event @P99", "Event gap @P50", "Event gap @P99", "Stream @P99",
+	})
+
+	for _, scenario := range rep.Scenarios {
+		if scenario.Stream == nil {
+			continue
+		}
+
+		tableWriter.AppendRow(table.Row{
+			scenario.Name,
+			scenario.Stream.Streams,
+			scenario.Stream.Events,
+			fmt.Sprintf("%.3f ms", scenario.TimingsMs[tracking.PhaseFirstEvent][latencyPercentileKey]),
+			fmt.Sprintf("%.3f ms", scenario.TimingsMs[tracking.PhaseEventGap][medianPercentileKey]),
+			fmt.Sprintf("%.3f ms", scenario.TimingsMs[tracking.PhaseEventGap][latencyPercentileKey]),
+			fmt.Sprintf("%.3f ms", scenario.TimingsMs[tracking.PhaseStream][latencyPercentileKey]),
+		})
+	}
+
+	tableWriter.SetStyle(table.StyleLight)
+
+	return tableWriter
+}
+
+func generateMetricsTable(rep *report.Report) table.Writer { //nolint: ireturn
+	tableWriter := table.NewWriter()
+	tableWriter.AppendHeader(table.Row{"Scenario", "Metrics"})
+
+	for _, scenario := range rep.Scenarios {
+		if len(scenario.Custom) == 0 {
 			continue
 		}
 
 		tableWriter.AppendRow(table.Row{
-			scenarioName,
-			counters.Counter(stats.CounterTotal),
-			counters.Counter(stats.CounterSuccess),
-			counters.Counter(stats.CounterFailed),
-			counters.Counter(stats.CounterTimeout),
-			counters.Counter(stats.CounterInvalid),
-			fmt.Sprintf("%d ms", counters.LatencyAtPercentile(latencyPercentile)),
-			results.Duration(scenarioName),
-			results.Rps(scenarioName),
-			results.ThresholdsPassed(scenarioName),
+			scenario.Name,
+			formatCounts(scenario.Custom),
 		})
 	}
 
@@ -50,23 +256,130 @@ func generateTable(conf *config.Config, results *stats.Stats) table.Writer { //n
 	return tableWriter
 }
 
-func generateTXT(conf *config.Config, results *stats.Stats, success bool) string {
+func formatCounts(counts map[string]int64) string {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s: %d", name, counts[name]))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func hasStages(rep *report.Report) bool {
+	for _, scenario := range rep.Scenarios {
+		if len(scenario.Stages) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasClosed(rep *report.Report) bool {
+	for _, scenario := range rep.Scenarios {
+		if scenario.Closed != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasStreams(rep *report.Report) bool {
+	for _, scenario := range rep.Scenarios {
+		if scenario.Stream != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasMetrics(rep *report.Report) bool {
+	for _, scenario := range rep.Scenarios {
+		if len(scenario.Custom) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasErrors(rep *report.Report) bool {
+	for _, scenario := range rep.Scenarios {
+		if len(scenario.Errors) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func generateTXT(rep *report.Report) string {
 	var output strings.Builder
 
-	output.WriteString(fmt.Sprintf("Name: %s\n", conf.Name))
+	output.WriteString(fmt.Sprintf("Name: %s\n", rep.Name))
 
-	if conf.FileName != "" {
-		output.WriteString(fmt.Sprintf("Path: %s\n", conf.FileName))
+	if rep.Path != "" {
+		output.WriteString(fmt.Sprintf("Path: %s\n", rep.Path))
 	}
 
-	tableWriter := generateTable(conf, results)
+	tableWriter := generateTable(rep)
 	output.WriteString(tableWriter.Render())
 
+	output.WriteString("\nTimings:\n")
+	output.WriteString(generateTimingsTable(rep).Render())
+
+	output.WriteString("\nTraffic:\n")
+	output.WriteString(generateTrafficTable(rep).Render())
+
+	output.WriteString("\nStatus codes:\n")
+	output.WriteString(generateCodesTable(rep).Render())
+
+	if hasStages(rep) {
+		output.WriteString("\nStages:\n")
+		output.WriteString(generateStagesTable(rep).Render())
+	}
+
+	if hasClosed(rep) {
+		output.WriteString("\nWebSocket connections:\n")
+		output.WriteString(generateClosedTable(rep).Render())
+	}
+
+	if hasStreams(rep) {
+		output.WriteString("\nStreams:\n")
+		output.WriteString(generateStreamsTable(rep).Render())
+	}
+
+	if hasMetrics(rep) {
+		output.WriteString("\nMetrics:\n")
+		output.WriteString(generateMetricsTable(rep).Render())
+	}
+
+	if hasErrors(rep) {
+		output.WriteString("\nErrors:\n")
+		output.WriteString(generateErrorsTable(rep).Render())
+		output.WriteString("\n")
+		output.WriteString(generateErrorSamplesTable(rep).Render())
+	}
+
+	if rep.Comparison != nil {
+		output.WriteString(fmt.Sprintf("\nBaseline: %s\n", rep.Comparison.Baseline))
+		output.WriteString(generateComparisonTable(rep.Comparison).Render())
+	}
+
 	output.WriteString(
-		fmt.Sprintf("\nTotal duration: %s\n", results.TotalDuration()),
+		fmt.Sprintf("\nTotal duration: %s\n", rep.Duration()),
 	)
 
-	if success {
+	if rep.Success {
 		output.WriteString("OK")
 	} else {
 		output.WriteString("Failed")
@@ -77,8 +390,47 @@ func generateTXT(conf *config.Config, results *stats.Stats, success bool) string
 	return output.String()
 }
 
-func generateCSV(conf *config.Config, results *stats.Stats) string {
-	tableWriter := generateTable(conf, results)
+func generateCSV(rep *report.Report) string {
+	tableWriter := generateTable(rep)
+
+	output := tableWriter.RenderCSV()
+	output += "\n\n" + generateTimingsTable(rep).RenderCSV()
+	output += "\n\n" + generateTrafficTable(rep).RenderCSV()
+	output += "\n\n" + generateCodesTable(rep).RenderCSV()
+
+	if hasStages(rep) {
+		output += "\n\n" + generateStagesTable(rep).RenderCSV()
+	}
+
+	if hasClosed(rep) {
+		output += "\n\n" + generateClosedTable(rep).RenderCSV()
+	}
+
+	if hasStreams(rep) {
+		output += "\n\n" + generateStreamsTable(rep).RenderCSV()
+	}
+
+	if hasMetrics(rep) {
+		output += "\n\n" + generateMetricsTable(rep).RenderCSV()
+	}
+
+	if hasErrors(rep) {
+		output += "\n\n" + generateErrorsTable(rep).RenderCSV()
+		output += "\n\n" + generateErrorSamplesTable(rep).RenderCSV()
+	}
+
+	if rep.Comparison != nil {
+		output += "\n\n" + generateComparisonTable(rep.Comparison).RenderCSV()
+	}
+
+	return output
+}
+
+func generateJSON(v any) string {
+	output, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		log.Error().Err(err).Msg("failed to generate json")
+	}
 
-	return tableWriter.RenderCSV()
+	return string(output)
 }
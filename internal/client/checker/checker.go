@@ -17,6 +17,7 @@ const (
 	TypeHTTPCode   = "httpCode"
 	TypeHTTPHeader = "httpHeader"
 	TypeHTTPBody   = "httpBody"
+	TypeCookie     = "cookie"
 )
 
 var ErrUnknownCheckType = errors.New("unknown check type")
@@ -67,6 +68,10 @@ func RunCheck(check *config.Check, response *http.Response) Result {
 		return CheckHTTPBody(check, response)
 	}
 
+	if check.Type == TypeCookie {
+		return CheckCookie(check, response)
+	}
+
 	return Result{
 		Error: ErrUnknownCheckType,
 	}
@@ -106,6 +111,38 @@ func CheckHTTPHeader(check *config.Check, response *http.Response) Result {
 	return result
 }
 
+// CheckCookie validates a cookie set by response, without equals and contains it passes if cookie is set.
+func CheckCookie(check *config.Check, response *http.Response) Result {
+	var result Result
+
+	found := false
+
+	for _, cookie := range response.Cookies() {
+		if cookie.Name == check.Name {
+			result.Actual = cookie.Value
+			found = true
+
+			break
+		}
+	}
+
+	if check.Equals != "" {
+		result.Pass = found && result.Actual == check.Equals
+
+		return result
+	}
+
+	if check.Contains != "" {
+		result.Pass = found && strings.Contains(result.Actual, check.Contains)
+
+		return result
+	}
+
+	result.Pass = found
+
+	return result
+}
+
 func CheckHTTPBody(check *config.Check, response *http.Response) Result {
 	var result Result
 
@@ -265,3 +265,52 @@ func TestCheckHTTPBody(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckCookie(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		check *config.Check
+		pass  bool
+	}{
+		{
+			name:  "cookie is set",
+			check: &config.Check{Name: "session"},
+			pass:  true,
+		},
+		{
+			name:  "cookie is missing",
+			check: &config.Check{Name: "token"},
+			pass:  false,
+		},
+		{
+			name:  "value is equal",
+			check: &config.Check{Name: "session", Equals: "abc123"},
+			pass:  true,
+		},
+		{
+			name:  "value contains",
+			check: &config.Check{Name: "session", Contains: "abc"},
+			pass:  true,
+		},
+		{
+			name:  "invalid value",
+			check: &config.Check{Name: "session", Equals: "xyz"},
+			pass:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			resp := &http.Response{Header: http.Header{"Set-Cookie": []string{"session=abc123; Path=/"}}}
+
+			result := checker.CheckCookie(tt.check, resp)
+			if result.Pass != tt.pass {
+				t.Errorf("pass returned %v; expected %v", result.Pass, tt.pass)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package checker
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/miekg/dns"
+)
+
+const (
+	TypeDNSRcode  = "dnsRcode"
+	TypeDNSAnswer = "dnsAnswer"
+)
+
+func (c *Checker) ValidateDNS(response *dns.Msg) ([]Result, bool) {
+	results := make([]Result, len(c.checks))
+
+	success := true
+
+	for i, check := range c.checks {
+		result := RunDNSCheck(check, response)
+		results[i] = result
+
+		if !result.Pass {
+			success = false
+		}
+	}
+
+	return results, success
+}
+
+func RunDNSCheck(check *config.Check, response *dns.Msg) Result {
+	if check.Type == TypeDNSRcode {
+		return CheckDNSRcode(check, response)
+	}
+
+	if check.Type == TypeDNSAnswer {
+		return CheckDNSAnswer(check, response)
+	}
+
+	return Result{
+		Error: ErrUnknownCheckType,
+	}
+}
+
+// CheckDNSRcode compares response code with a name, e.g. NOERROR or NXDOMAIN, or a number.
+func CheckDNSRcode(check *config.Check, response *dns.Msg) Result {
+	var result Result
+
+	result.Actual = dns.RcodeToString[response.Rcode]
+
+	// NOERROR is expected by default
+	if check.Equals == "" {
+		result.Pass = response.Rcode == dns.RcodeSuccess
+
+		return result
+	}
+
+	if number, err := strconv.Atoi(check.Equals); err == nil {
+		result.Pass = response.Rcode == number
+	} else {
+		result.Pass = strings.EqualFold(result.Actual, check.Equals)
+	}
+
+	return result
+}
+
+// CheckDNSAnswer validates records of answer section, name filters them by type, e.g. A or SRV.
+// It passes if any record passes, without equals and contains a record must be present.
+func CheckDNSAnswer(check *config.Check, response *dns.Msg) Result {
+	var result Result
+
+	for _, record := range response.Answer {
+		if check.Name != "" && !strings.EqualFold(dns.TypeToString[record.Header().Rrtype], check.Name) {
+			continue
+		}
+
+		value := RecordValue(record)
+		result.Actual = TruncBody(value)
+
+		switch {
+		case check.Equals != "":
+			result.Pass = value == check.Equals
+		case check.Contains != "":
+			result.Pass = strings.Contains(value, check.Contains)
+		default:
+			result.Pass = true
+		}
+
+		if result.Pass {
+			break
+		}
+	}
+
+	return result
+}
+
+// RecordValue returns data of a record without header, e.g. an IP address of A record,
+// "priority weight port target" of SRV record or joined strings of TXT record.
+func RecordValue(record dns.RR) string {
+	if txt, ok := record.(*dns.TXT); ok {
+		return strings.Join(txt.Txt, "")
+	}
+
+	return strings.TrimPrefix(record.String(), record.Header().String())
+}
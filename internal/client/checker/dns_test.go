@@ -0,0 +1,85 @@
+package checker_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/lameaux/bro/internal/client/checker"
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/miekg/dns"
+)
+
+func TestRunDNSCheck(t *testing.T) {
+	t.Parallel()
+
+	header := func(rrtype uint16) dns.RR_Header {
+		return dns.RR_Header{Name: "bro.test.", Rrtype: rrtype, Class: dns.ClassINET, Ttl: 60}
+	}
+
+	response := &dns.Msg{
+		Answer: []dns.RR{
+			&dns.A{Hdr: header(dns.TypeA), A: net.ParseIP("10.0.0.1")},
+			&dns.A{Hdr: header(dns.TypeA), A: net.ParseIP("10.0.0.2")},
+			&dns.SRV{Hdr: header(dns.TypeSRV), Priority: 10, Weight: 5, Port: 8080, Target: "api.bro.test."},
+			&dns.TXT{Hdr: header(dns.TypeTXT), Txt: []string{"v=spf1 ", "-all"}},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		check *config.Check
+		pass  bool
+	}{
+		{
+			name:  "rcode name",
+			check: &config.Check{Type: checker.TypeDNSRcode, Equals: "noerror"},
+			pass:  true,
+		},
+		{
+			name:  "rcode default",
+			check: &config.Check{Type: checker.TypeDNSRcode},
+			pass:  true,
+		},
+		{
+			name:  "rcode number",
+			check: &config.Check{Type: checker.TypeDNSRcode, Equals: "3"},
+			pass:  false,
+		},
+		{
+			name:  "any record of type",
+			check: &config.Check{Type: checker.TypeDNSAnswer, Name: "A", Equals: "10.0.0.2"},
+			pass:  true,
+		},
+		{
+			name:  "srv record",
+			check: &config.Check{Type: checker.TypeDNSAnswer, Name: "SRV", Equals: "10 5 8080 api.bro.test."},
+			pass:  true,
+		},
+		{
+			name:  "txt record",
+			check: &config.Check{Type: checker.TypeDNSAnswer, Name: "TXT", Equals: "v=spf1 -all"},
+			pass:  true,
+		},
+		{
+			name:  "missing type",
+			check: &config.Check{Type: checker.TypeDNSAnswer, Name: "AAAA"},
+			pass:  false,
+		},
+		{
+			name:  "any answer",
+			check: &config.Check{Type: checker.TypeDNSAnswer},
+			pass:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := checker.RunDNSCheck(tt.check, response)
+			if result.Pass != tt.pass {
+				t.Errorf("pass returned %v; expected %v (actual %q)", result.Pass, tt.pass, result.Actual)
+			}
+		})
+	}
+}
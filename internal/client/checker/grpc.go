@@ -0,0 +1,128 @@
+package checker
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/lameaux/bro/internal/client/grpcclient"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	TypeGRPCCode  = "grpcCode"
+	TypeGRPCField = "grpcField"
+)
+
+var errFieldNotFound = errors.New("field not found")
+
+func (c *Checker) ValidateGRPC(response *grpcclient.Response) ([]Result, bool) {
+	results := make([]Result, len(c.checks))
+
+	success := true
+
+	for i, check := range c.checks {
+		result := RunGRPCCheck(check, response)
+		results[i] = result
+
+		if !result.Pass {
+			success = false
+		}
+	}
+
+	return results, success
+}
+
+func RunGRPCCheck(check *config.Check, response *grpcclient.Response) Result {
+	if check.Type == TypeGRPCCode {
+		return CheckGRPCCode(check, response)
+	}
+
+	if check.Type == TypeGRPCField {
+		return CheckGRPCField(check, response)
+	}
+
+	return Result{
+		Error: ErrUnknownCheckType,
+	}
+}
+
+// CheckGRPCCode compares status with a code name, e.g. OK, NOT_FOUND or NotFound, or a number.
+func CheckGRPCCode(check *config.Check, response *grpcclient.Response) Result {
+	var result Result
+
+	result.Actual = response.Code.String()
+
+	if check.Equals != "" {
+		result.Pass = grpcCodeEquals(response.Code, check.Equals)
+
+		return result
+	}
+
+	return result
+}
+
+func grpcCodeEquals(code codes.Code, expected string) bool {
+	if number, err := strconv.Atoi(expected); err == nil {
+		return int(code) == number
+	}
+
+	return strings.EqualFold(code.String(), strings.ReplaceAll(expected, "_", ""))
+}
+
+// CheckGRPCField validates a field of response message addressed by a path of JSON names, e.g. user.id or items.0.
+// Server-streaming responses pass if any message passes, without equals and contains a field must be set.
+func CheckGRPCField(check *config.Check, response *grpcclient.Response) Result {
+	result := Result{Error: errFieldNotFound}
+
+	for _, msg := range response.Messages {
+		result = checkGRPCField(check, msg)
+		if result.Pass {
+			break
+		}
+	}
+
+	return result
+}
+
+func checkGRPCField(check *config.Check, msg proto.Message) Result {
+	var result Result
+
+	value, err := messageField(msg, check.Name)
+	if err != nil {
+		result.Error = err
+
+		return result
+	}
+
+	result.Actual = TruncBody(value)
+
+	if check.Equals != "" {
+		result.Pass = value == check.Equals
+
+		return result
+	}
+
+	if check.Contains != "" {
+		result.Pass = strings.Contains(value, check.Contains)
+
+		return result
+	}
+
+	result.Pass = true
+
+	return result
+}
+
+func messageField(msg proto.Message, path string) (string, error) {
+	data, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(msg)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode message: %w", err)
+	}
+
+	return jsonField(data, path)
+}
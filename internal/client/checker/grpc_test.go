@@ -0,0 +1,123 @@
+package checker_test
+
+import (
+	"testing"
+
+	"github.com/lameaux/bro/internal/client/checker"
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/lameaux/bro/internal/client/grpcclient"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestCheckGRPCCode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		code   codes.Code
+		equals string
+		pass   bool
+	}{
+		{name: "name", code: codes.OK, equals: "OK", pass: true},
+		{name: "camel case name", code: codes.NotFound, equals: "NotFound", pass: true},
+		{name: "upper case name", code: codes.NotFound, equals: "NOT_FOUND", pass: true},
+		{name: "number", code: codes.NotFound, equals: "5", pass: true},
+		{name: "invalid code", code: codes.Internal, equals: "OK", pass: false},
+		{name: "invalid check", code: codes.OK, pass: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			check := &config.Check{Type: checker.TypeGRPCCode, Equals: tt.equals}
+
+			result := checker.RunGRPCCheck(check, &grpcclient.Response{Code: tt.code})
+			if result.Pass != tt.pass {
+				t.Errorf("pass returned %v; expected %v", result.Pass, tt.pass)
+			}
+
+			if result.Actual != tt.code.String() {
+				t.Errorf("actual equals %v; expected %v", result.Actual, tt.code.String())
+			}
+		})
+	}
+}
+
+func TestCheckGRPCField(t *testing.T) {
+	t.Parallel()
+
+	newMessage := func(value map[string]any) proto.Message {
+		msg, err := structpb.NewStruct(value)
+		if err != nil {
+			t.Fatalf("failed to create message: %v", err)
+		}
+
+		return msg
+	}
+
+	resp := &grpcclient.Response{
+		Code: codes.OK,
+		Messages: []proto.Message{
+			newMessage(map[string]any{"user": map[string]any{"name": "alice", "age": 30}}),
+			newMessage(map[string]any{"items": []any{"a", "b"}, "user": map[string]any{"name": "bob"}}),
+		},
+	}
+
+	tests := []struct {
+		name  string
+		check *config.Check
+		pass  bool
+	}{
+		{
+			name:  "field is set",
+			check: &config.Check{Name: "user.name"},
+			pass:  true,
+		},
+		{
+			name:  "field is missing",
+			check: &config.Check{Name: "user.email"},
+			pass:  false,
+		},
+		{
+			name:  "value is equal",
+			check: &config.Check{Name: "user.age", Equals: "30"},
+			pass:  true,
+		},
+		{
+			name:  "any message matches",
+			check: &config.Check{Name: "user.name", Equals: "bob"},
+			pass:  true,
+		},
+		{
+			name:  "array element",
+			check: &config.Check{Name: "items.1", Equals: "b"},
+			pass:  true,
+		},
+		{
+			name:  "value contains",
+			check: &config.Check{Name: "items", Contains: `"a"`},
+			pass:  true,
+		},
+		{
+			name:  "invalid value",
+			check: &config.Check{Name: "user.name", Equals: "carol"},
+			pass:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tt.check.Type = checker.TypeGRPCField
+
+			result := checker.RunGRPCCheck(tt.check, resp)
+			if result.Pass != tt.pass {
+				t.Errorf("pass returned %v; expected %v (actual %q, err %v)", result.Pass, tt.pass, result.Actual, result.Error)
+			}
+		})
+	}
+}
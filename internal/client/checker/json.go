@@ -0,0 +1,92 @@
+package checker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lameaux/bro/internal/client/config"
+)
+
+// jsonField returns a value addressed by a path of field names and array indexes, e.g. items.0.id.
+// Strings and numbers are returned as is, objects and arrays as JSON.
+func jsonField(data []byte, path string) (string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return "", fmt.Errorf("failed to decode message: %w", err)
+	}
+
+	for _, key := range strings.Split(path, ".") {
+		switch node := value.(type) {
+		case map[string]any:
+			field, ok := node[key]
+			if !ok {
+				return "", fmt.Errorf("%w: %s", errFieldNotFound, path)
+			}
+
+			value = field
+		case []any:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(node) {
+				return "", fmt.Errorf("%w: %s", errFieldNotFound, path)
+			}
+
+			value = node[index]
+		default:
+			return "", fmt.Errorf("%w: %s", errFieldNotFound, path)
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	default:
+		encoded, _ := json.Marshal(v)
+
+		return string(encoded), nil
+	}
+}
+
+// checkMessage validates a message, or its JSON field if name is set.
+// Without equals and contains a field must be set.
+func checkMessage(check *config.Check, message []byte) Result {
+	var result Result
+
+	value := string(message)
+
+	if check.Name != "" {
+		field, err := jsonField(message, check.Name)
+		if err != nil {
+			result.Error = err
+
+			return result
+		}
+
+		value = field
+	}
+
+	result.Actual = TruncBody(value)
+
+	if check.Equals != "" {
+		result.Pass = value == check.Equals
+
+		return result
+	}
+
+	if check.Contains != "" {
+		result.Pass = strings.Contains(value, check.Contains)
+
+		return result
+	}
+
+	result.Pass = check.Name != ""
+
+	return result
+}
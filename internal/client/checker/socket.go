@@ -0,0 +1,38 @@
+package checker
+
+import "github.com/lameaux/bro/internal/client/config"
+
+const TypeSocketResponse = "socketResponse"
+
+func (c *Checker) ValidateSocket(response []byte) ([]Result, bool) {
+	results := make([]Result, len(c.checks))
+
+	success := true
+
+	for i, check := range c.checks {
+		result := RunSocketCheck(check, response)
+		results[i] = result
+
+		if !result.Pass {
+			success = false
+		}
+	}
+
+	return results, success
+}
+
+func RunSocketCheck(check *config.Check, response []byte) Result {
+	if check.Type == TypeSocketResponse {
+		return CheckSocketResponse(check, response)
+	}
+
+	return Result{
+		Error: ErrUnknownCheckType,
+	}
+}
+
+// CheckSocketResponse validates a response of tcp or udp request, or its JSON field if name is set.
+// Without equals and contains a field must be set.
+func CheckSocketResponse(check *config.Check, response []byte) Result {
+	return checkMessage(check, response)
+}
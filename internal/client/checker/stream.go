@@ -0,0 +1,111 @@
+package checker
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/lameaux/bro/internal/client/config"
+)
+
+const (
+	TypeEvent     = "event"
+	TypeEventType = "eventType"
+
+	// defaultEventType is a type of sse events without event field.
+	defaultEventType = "message"
+)
+
+var errNoEvents = errors.New("no events received")
+
+// Event is a message of a streaming response, type is set for sse events only.
+type Event struct {
+	Type string
+	Data []byte
+}
+
+// StreamChecker validates events of a stream as they arrive, a check passes if any event passes it.
+type StreamChecker struct {
+	checks  []*config.Check
+	results []Result
+}
+
+func NewStreamChecker(checks []*config.Check) *StreamChecker {
+	results := make([]Result, len(checks))
+	for i := range results {
+		results[i].Error = errNoEvents
+	}
+
+	return &StreamChecker{
+		checks:  checks,
+		results: results,
+	}
+}
+
+func (c *StreamChecker) Validate(event Event) {
+	for i, check := range c.checks {
+		if c.results[i].Pass {
+			continue
+		}
+
+		c.results[i] = RunStreamCheck(check, event)
+	}
+}
+
+// Results returns results of checks, they fail if stream had no events.
+func (c *StreamChecker) Results() ([]Result, bool) {
+	success := true
+
+	for _, result := range c.results {
+		if !result.Pass {
+			success = false
+		}
+	}
+
+	return c.results, success
+}
+
+func RunStreamCheck(check *config.Check, event Event) Result {
+	if check.Type == TypeEvent {
+		return CheckEvent(check, event)
+	}
+
+	if check.Type == TypeEventType {
+		return CheckEventType(check, event)
+	}
+
+	return Result{
+		Error: ErrUnknownCheckType,
+	}
+}
+
+// CheckEvent validates event data, or its JSON field if name is set, e.g. choices.0.text.
+// Without equals and contains a field must be set.
+func CheckEvent(check *config.Check, event Event) Result {
+	return checkMessage(check, event.Data)
+}
+
+// CheckEventType compares sse event field, events without it have message type.
+func CheckEventType(check *config.Check, event Event) Result {
+	var result Result
+
+	eventType := event.Type
+	if eventType == "" {
+		eventType = defaultEventType
+	}
+
+	result.Actual = eventType
+
+	if check.Equals != "" {
+		result.Pass = eventType == check.Equals
+
+		return result
+	}
+
+	if check.Contains != "" {
+		result.Pass = strings.Contains(eventType, check.Contains)
+
+		return result
+	}
+
+	return result
+}
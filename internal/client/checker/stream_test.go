@@ -0,0 +1,81 @@
+package checker_test
+
+import (
+	"testing"
+
+	"github.com/lameaux/bro/internal/client/checker"
+	"github.com/lameaux/bro/internal/client/config"
+)
+
+func TestStreamChecker(t *testing.T) {
+	t.Parallel()
+
+	events := []checker.Event{
+		{Data: []byte(`{"delta": "Hel"}`)},
+		{Type: "done", Data: []byte(`{"delta": "lo", "finish": "stop"}`)},
+	}
+
+	tests := []struct {
+		name  string
+		check *config.Check
+		pass  bool
+	}{
+		{
+			name:  "any event contains",
+			check: &config.Check{Type: checker.TypeEvent, Contains: "lo"},
+			pass:  true,
+		},
+		{
+			name:  "field of an event is equal",
+			check: &config.Check{Type: checker.TypeEvent, Name: "finish", Equals: "stop"},
+			pass:  true,
+		},
+		{
+			name:  "no event has value",
+			check: &config.Check{Type: checker.TypeEvent, Name: "delta", Equals: "Hello"},
+			pass:  false,
+		},
+		{
+			name:  "named event",
+			check: &config.Check{Type: checker.TypeEventType, Equals: "done"},
+			pass:  true,
+		},
+		{
+			name:  "default event type",
+			check: &config.Check{Type: checker.TypeEventType, Equals: "message"},
+			pass:  true,
+		},
+		{
+			name:  "unknown check type",
+			check: &config.Check{Type: "httpCode", Equals: "200"},
+			pass:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			streamChecker := checker.NewStreamChecker([]*config.Check{tt.check})
+			for _, event := range events {
+				streamChecker.Validate(event)
+			}
+
+			results, success := streamChecker.Results()
+			if success != tt.pass {
+				t.Errorf("pass returned %v; expected %v (actual %q)", success, tt.pass, results[0].Actual)
+			}
+		})
+	}
+}
+
+func TestStreamChecker_NoEvents(t *testing.T) {
+	t.Parallel()
+
+	streamChecker := checker.NewStreamChecker([]*config.Check{{Type: checker.TypeEvent}})
+
+	results, success := streamChecker.Results()
+	if success || results[0].Error == nil {
+		t.Errorf("expected checks to fail without events")
+	}
+}
@@ -0,0 +1,38 @@
+package checker
+
+import "github.com/lameaux/bro/internal/client/config"
+
+const TypeWSMessage = "wsMessage"
+
+func (c *Checker) ValidateWebSocket(message []byte) ([]Result, bool) {
+	results := make([]Result, len(c.checks))
+
+	success := true
+
+	for i, check := range c.checks {
+		result := RunWebSocketCheck(check, message)
+		results[i] = result
+
+		if !result.Pass {
+			success = false
+		}
+	}
+
+	return results, success
+}
+
+func RunWebSocketCheck(check *config.Check, message []byte) Result {
+	if check.Type == TypeWSMessage {
+		return CheckWSMessage(check, message)
+	}
+
+	return Result{
+		Error: ErrUnknownCheckType,
+	}
+}
+
+// CheckWSMessage validates a received message, or its JSON field if name is set, e.g. user.id.
+// Without equals and contains a field must be set.
+func CheckWSMessage(check *config.Check, message []byte) Result {
+	return checkMessage(check, message)
+}
@@ -0,0 +1,64 @@
+package checker_test
+
+import (
+	"testing"
+
+	"github.com/lameaux/bro/internal/client/checker"
+	"github.com/lameaux/bro/internal/client/config"
+)
+
+func TestCheckWSMessage(t *testing.T) {
+	t.Parallel()
+
+	message := []byte(`{"type": "joined", "room": {"id": 7}}`)
+
+	tests := []struct {
+		name  string
+		check *config.Check
+		pass  bool
+	}{
+		{
+			name:  "message contains",
+			check: &config.Check{Contains: "joined"},
+			pass:  true,
+		},
+		{
+			name:  "field is equal",
+			check: &config.Check{Name: "room.id", Equals: "7"},
+			pass:  true,
+		},
+		{
+			name:  "field is set",
+			check: &config.Check{Name: "type"},
+			pass:  true,
+		},
+		{
+			name:  "field is missing",
+			check: &config.Check{Name: "user"},
+			pass:  false,
+		},
+		{
+			name:  "invalid value",
+			check: &config.Check{Name: "type", Equals: "left"},
+			pass:  false,
+		},
+		{
+			name:  "invalid check",
+			check: &config.Check{},
+			pass:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tt.check.Type = checker.TypeWSMessage
+
+			result := checker.RunWebSocketCheck(tt.check, message)
+			if result.Pass != tt.pass {
+				t.Errorf("pass returned %v; expected %v (actual %q)", result.Pass, tt.pass, result.Actual)
+			}
+		})
+	}
+}
@@ -7,3 +7,19 @@ type Check struct {
 	Contains string `yaml:"contains"`
 	Matches  string `yaml:"matches"`
 }
+
+// Extractor saves a value from response into a variable of the thread, it can be used as ${var} in url and body.
+type Extractor struct {
+	Type string `yaml:"type"`
+	Name string `yaml:"name"`
+	// Var is a variable name, defaults to Name.
+	Var string `yaml:"var"`
+}
+
+func (e *Extractor) VarName() string {
+	if e.Var != "" {
+		return e.Var
+	}
+
+	return e.Name
+}
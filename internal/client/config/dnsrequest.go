@@ -0,0 +1,40 @@
+package config
+
+import "time"
+
+const (
+	DNSTransportUDP = "udp"
+	DNSTransportTCP = "tcp"
+
+	defaultDNSType    = "A"
+	defaultDNSTimeout = 2 * time.Second
+)
+
+// DNSRequest is a DNS query, scenario sends it instead of httpRequest when set.
+type DNSRequest struct {
+	// Server is host:port of a DNS server, port defaults to 53.
+	Server string `yaml:"server"`
+	// Name is a domain name to query, can use ${var}.
+	Name string `yaml:"name"`
+	// TypeRaw is a record type, e.g. A, AAAA, SRV or TXT. Defaults to A.
+	TypeRaw string `yaml:"type"`
+	// TransportRaw is udp (default) or tcp.
+	TransportRaw string `yaml:"transport"`
+	// NoRecursion clears RD flag, e.g. to query authoritative servers.
+	NoRecursion bool `yaml:"noRecursion"`
+
+	// Timeout of a query. Defaults to 2s.
+	TimeoutRaw time.Duration `yaml:"timeout"`
+}
+
+func (r *DNSRequest) Type() string {
+	return StringOrDefault(r.TypeRaw, defaultDNSType)
+}
+
+func (r *DNSRequest) Transport() string {
+	return StringOrDefault(r.TransportRaw, DNSTransportUDP)
+}
+
+func (r *DNSRequest) Timeout() time.Duration {
+	return DurationOrDefault(r.TimeoutRaw, defaultDNSTimeout)
+}
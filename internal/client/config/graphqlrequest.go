@@ -0,0 +1,16 @@
+package config
+
+// GraphQLRequest is a GraphQL operation posted as JSON, scenario sends it instead of httpRequest when set.
+// Other httpRequest settings still apply, e.g. acceptEncoding or unixSocket.
+type GraphQLRequest struct {
+	// URL of GraphQL endpoint, e.g. http://localhost:8080/graphql.
+	URL string `yaml:"url"`
+	// Query is a GraphQL document, can use ${var}.
+	Query string `yaml:"query"`
+	// QueryFile is a path to a file with GraphQL document, used instead of query.
+	QueryFile string         `yaml:"queryFile"`
+	Variables map[string]any `yaml:"variables"`
+	// OperationName selects an operation of a document with several ones.
+	// Requests are labeled with it in stats and brod, with the name of the first operation if not set.
+	OperationName string `yaml:"operationName"`
+}
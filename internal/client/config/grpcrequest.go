@@ -0,0 +1,26 @@
+package config
+
+import "time"
+
+// GRPCRequest is a dynamic gRPC call, scenario sends it instead of httpRequest when set.
+type GRPCRequest struct {
+	// Target is a server address, e.g. localhost:50051 or dns:///example.com:443.
+	Target string `yaml:"target"`
+	// Method is a full method name, e.g. package.Service/Method.
+	Method string `yaml:"method"`
+	// Message is a JSON encoded request message, empty message is sent if not set.
+	Message  string            `yaml:"message"`
+	Metadata map[string]string `yaml:"metadata"`
+
+	// ProtoFiles are .proto files describing the service, resolved relative to ImportPaths.
+	ProtoFiles  []string `yaml:"protoFiles"`
+	ImportPaths []string `yaml:"importPaths"`
+	// Protoset is a file with serialized FileDescriptorSet, e.g. protoc --include_imports -o.
+	// Server reflection is used when neither protoFiles nor protoset are set.
+	Protoset string `yaml:"protoset"`
+
+	// TLS enables transport security, plaintext is used if not set.
+	TLS *TLS `yaml:"tls"`
+	// Timeout of a call, server-streaming calls must receive all messages within it.
+	Timeout time.Duration `yaml:"timeout"`
+}
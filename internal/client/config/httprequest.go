@@ -1,29 +1,108 @@
 package config
 
 import (
-	"io"
 	"net/http"
 	"strings"
 )
 
+const (
+	unixSocketScheme = "unix://"
+
+	AcceptEncodingNone    = "none"
+	defaultAcceptEncoding = "gzip"
+)
+
 type HTTPRequest struct {
 	URL       string  `yaml:"url"`
 	MethodRaw string  `yaml:"method"`
 	BodyRaw   *string `yaml:"body"`
+
+	// Only one of body, bodyFile, bodyBase64, form and multipart can be set.
+	BodyFile   string            `yaml:"bodyFile"`
+	BodyBase64 string            `yaml:"bodyBase64"`
+	Form       map[string]string `yaml:"form"`
+	Multipart  []*MultipartField `yaml:"multipart"`
+
+	// UnixSocket is a path to unix domain socket to send requests to, URL host is sent as Host header.
+	UnixSocket string `yaml:"unixSocket"`
+
+	// AcceptEncoding is a list of response encodings, e.g. "gzip, br, zstd", or none. Defaults to gzip.
+	AcceptEncoding string `yaml:"acceptEncoding"`
+	// Compress encodes request body with gzip, br or zstd and sets Content-Encoding.
+	Compress string `yaml:"compress"`
+
+	// Stream reads response body as a stream of events, see Stream.
+	Stream *Stream `yaml:"stream"`
 }
 
-func (r *HTTPRequest) Method() string {
-	if r.MethodRaw != "" {
-		return r.MethodRaw
+const (
+	StreamFormatSSE    = "sse"
+	StreamFormatLines  = "lines"
+	StreamFormatChunks = "chunks"
+)
+
+// Stream measures time to first event, gaps between events and stream duration.
+type Stream struct {
+	// Format is sse (text/event-stream), lines (newline delimited, e.g. chunked JSON) or chunks. Defaults to sse.
+	FormatRaw string `yaml:"format"`
+	// Checks are validated on every event, a check passes if any event of the stream passes it.
+	Checks []*Check `yaml:"checks"`
+}
+
+func (s *Stream) Format() string {
+	if s.FormatRaw != "" {
+		return s.FormatRaw
 	}
 
-	return http.MethodGet
+	return StreamFormatSSE
+}
+
+// MultipartField is a value or a file part of multipart/form-data body.
+type MultipartField struct {
+	Name  string `yaml:"name"`
+	Value string `yaml:"value"`
+	File  string `yaml:"file"`
+	// FileName defaults to base name of the file.
+	FileName string `yaml:"fileName"`
+	// ContentType defaults to a type detected by file extension.
+	ContentType string `yaml:"contentType"`
+}
+
+func (r *HTTPRequest) HasBody() bool {
+	return r.BodyRaw != nil || r.BodyFile != "" || r.BodyBase64 != "" || len(r.Form) > 0 || len(r.Multipart) > 0
+}
+
+// AcceptEncodingHeader returns value of Accept-Encoding header, empty if it should not be sent.
+func (r *HTTPRequest) AcceptEncodingHeader() string {
+	switch r.AcceptEncoding {
+	case "":
+		return defaultAcceptEncoding
+	case AcceptEncodingNone:
+		return ""
+	}
+
+	return r.AcceptEncoding
+}
+
+// Target returns URL to request and unix socket to dial if any.
+// URL in the form of unix:///path/to.sock:/request/path is split into both.
+func (r *HTTPRequest) Target() (string, string) {
+	if rest, ok := strings.CutPrefix(r.URL, unixSocketScheme); ok {
+		socket, path, _ := strings.Cut(rest, ":")
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+
+		return "http://localhost" + path, socket
+	}
+
+	return r.URL, r.UnixSocket
 }
 
-func (r *HTTPRequest) BodyReader() io.Reader {
-	if r.BodyRaw != nil {
-		return strings.NewReader(*r.BodyRaw)
+func (r *HTTPRequest) Method() string {
+	if r.MethodRaw != "" {
+		return r.MethodRaw
 	}
 
-	return nil
+	return http.MethodGet
 }
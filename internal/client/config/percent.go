@@ -0,0 +1,50 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var errInvalidPercent = errors.New("invalid percent")
+
+// Percent is a ratio that can be written either as "10%" or as 0.1.
+type Percent float64
+
+func ParsePercent(s string) (Percent, error) {
+	s = strings.TrimSpace(s)
+
+	if value, ok := strings.CutSuffix(s, "%"); ok {
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %s", errInvalidPercent, s)
+		}
+
+		return Percent(f / 100), nil //nolint:mnd
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", errInvalidPercent, s)
+	}
+
+	return Percent(f), nil
+}
+
+func (p *Percent) UnmarshalYAML(value *yaml.Node) error {
+	parsed, err := ParsePercent(value.Value)
+	if err != nil {
+		return err
+	}
+
+	*p = parsed
+
+	return nil
+}
+
+func (p Percent) String() string {
+	return strconv.FormatFloat(float64(p)*100, 'f', -1, 64) + "%" //nolint:mnd
+}
@@ -2,10 +2,39 @@ package config
 
 import "time"
 
+const (
+	ScenarioTypeHTTP      = "http"
+	ScenarioTypeGraphQL   = "graphql"
+	ScenarioTypeGRPC      = "grpc"
+	ScenarioTypeWebSocket = "websocket"
+	ScenarioTypeTCP       = "tcp"
+	ScenarioTypeUDP       = "udp"
+	ScenarioTypeDNS       = "dns"
+)
+
 type Scenario struct {
 	Name string `yaml:"name"`
+	// TypeRaw selects an executor of requests, it is detected by request settings if not set, see Type.
+	TypeRaw string `yaml:"type"`
+	// Params are settings of a custom executor.
+	Params map[string]any `yaml:"params"`
 
 	HTTPRequest HTTPRequest `yaml:"httpRequest"`
+	// GRPCRequest is sent instead of HTTPRequest when set.
+	GRPCRequest *GRPCRequest `yaml:"grpcRequest"`
+	// WebSocket is used instead of HTTPRequest when set.
+	WebSocket *WebSocket `yaml:"websocket"`
+	// GraphQLRequest is sent instead of HTTPRequest when set.
+	GraphQLRequest *GraphQLRequest `yaml:"graphqlRequest"`
+	// TCPRequest and UDPRequest are sent instead of HTTPRequest when set.
+	TCPRequest *SocketRequest `yaml:"tcpRequest"`
+	UDPRequest *SocketRequest `yaml:"udpRequest"`
+	// DNSRequest is sent instead of HTTPRequest when set.
+	DNSRequest *DNSRequest `yaml:"dnsRequest"`
+	// Script runs hooks before requests and after responses of http and graphql scenarios.
+	Script *Script `yaml:"script"`
+	// HTTPClient overrides global httpClient settings for this scenario.
+	HTTPClient *HTTPClient `yaml:"httpClient"`
 
 	RpsRaw      int           `yaml:"rps"`
 	DurationRaw time.Duration `yaml:"duration"`
@@ -14,9 +43,32 @@ type Scenario struct {
 	Stages []*Stage `yaml:"stages"`
 
 	Checks     []*Check     `yaml:"checks"`
+	Extract    []*Extractor `yaml:"extract"`
 	Thresholds []*Threshold `yaml:"thresholds"`
 }
 
+// Type returns scenario type set explicitly or by the request it sends, http by default.
+func (s *Scenario) Type() string {
+	switch {
+	case s.TypeRaw != "":
+		return s.TypeRaw
+	case s.GRPCRequest != nil:
+		return ScenarioTypeGRPC
+	case s.WebSocket != nil:
+		return ScenarioTypeWebSocket
+	case s.TCPRequest != nil:
+		return ScenarioTypeTCP
+	case s.UDPRequest != nil:
+		return ScenarioTypeUDP
+	case s.DNSRequest != nil:
+		return ScenarioTypeDNS
+	case s.GraphQLRequest != nil:
+		return ScenarioTypeGraphQL
+	default:
+		return ScenarioTypeHTTP
+	}
+}
+
 func (s *Scenario) Rps() int {
 	return max(s.RpsRaw, 1)
 }
@@ -34,19 +86,74 @@ func MergeScenarios(scenario *Scenario, defaults *Scenario) *Scenario {
 		return scenario
 	}
 
+	scenario.TypeRaw = StringOrDefault(scenario.TypeRaw, defaults.TypeRaw)
+
+	if scenario.Params == nil {
+		scenario.Params = defaults.Params
+	}
+
 	scenario.RpsRaw = IntOrDefault(scenario.RpsRaw, defaults.RpsRaw)
 	scenario.DurationRaw = DurationOrDefault(scenario.DurationRaw, defaults.DurationRaw)
 	scenario.ThreadsRaw = IntOrDefault(scenario.ThreadsRaw, defaults.ThreadsRaw)
 
 	scenario.HTTPRequest.URL = StringOrDefault(scenario.HTTPRequest.URL, defaults.HTTPRequest.URL)
 	scenario.HTTPRequest.MethodRaw = StringOrDefault(scenario.HTTPRequest.MethodRaw, defaults.HTTPRequest.MethodRaw)
-	scenario.HTTPRequest.BodyRaw = PStringOrDefault(scenario.HTTPRequest.BodyRaw, defaults.HTTPRequest.BodyRaw)
+	scenario.HTTPRequest.UnixSocket = StringOrDefault(scenario.HTTPRequest.UnixSocket, defaults.HTTPRequest.UnixSocket)
+	scenario.HTTPRequest.AcceptEncoding = StringOrDefault(
+		scenario.HTTPRequest.AcceptEncoding, defaults.HTTPRequest.AcceptEncoding,
+	)
+	scenario.HTTPRequest.Compress = StringOrDefault(scenario.HTTPRequest.Compress, defaults.HTTPRequest.Compress)
+
+	if scenario.HTTPRequest.Stream == nil {
+		scenario.HTTPRequest.Stream = defaults.HTTPRequest.Stream
+	}
+
+	if !scenario.HTTPRequest.HasBody() {
+		scenario.HTTPRequest.BodyRaw = defaults.HTTPRequest.BodyRaw
+		scenario.HTTPRequest.BodyFile = defaults.HTTPRequest.BodyFile
+		scenario.HTTPRequest.BodyBase64 = defaults.HTTPRequest.BodyBase64
+		scenario.HTTPRequest.Form = defaults.HTTPRequest.Form
+		scenario.HTTPRequest.Multipart = defaults.HTTPRequest.Multipart
+	}
+
+	if scenario.GRPCRequest == nil {
+		scenario.GRPCRequest = defaults.GRPCRequest
+	}
+
+	if scenario.WebSocket == nil {
+		scenario.WebSocket = defaults.WebSocket
+	}
+
+	if scenario.GraphQLRequest == nil {
+		scenario.GraphQLRequest = defaults.GraphQLRequest
+	}
+
+	if scenario.TCPRequest == nil {
+		scenario.TCPRequest = defaults.TCPRequest
+	}
+
+	if scenario.UDPRequest == nil {
+		scenario.UDPRequest = defaults.UDPRequest
+	}
+
+	if scenario.DNSRequest == nil {
+		scenario.DNSRequest = defaults.DNSRequest
+	}
+
+	if scenario.Script == nil {
+		scenario.Script = defaults.Script
+	}
+
+	if scenario.HTTPClient == nil {
+		scenario.HTTPClient = defaults.HTTPClient
+	}
 
 	if len(scenario.Stages) == 0 {
 		scenario.Stages = append(scenario.Stages, defaults.Stages...)
 	}
 
 	scenario.Checks = append(scenario.Checks, defaults.Checks...)
+	scenario.Extract = append(scenario.Extract, defaults.Extract...)
 	scenario.Thresholds = append(scenario.Thresholds, defaults.Thresholds...)
 
 	return scenario
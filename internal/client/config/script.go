@@ -0,0 +1,10 @@
+package config
+
+// Script is JavaScript code with hooks of http and graphql scenarios, it runs in a VM per sender thread.
+// Hooks are functions named init, beforeRequest and afterResponse, all of them are optional.
+type Script struct {
+	// Source is inline code.
+	Source string `yaml:"source"`
+	// File is a path to a script, used instead of source.
+	File string `yaml:"file"`
+}
@@ -0,0 +1,40 @@
+package config
+
+import "time"
+
+const defaultSocketTimeout = 5 * time.Second
+
+// SocketRequest sends a payload over a raw TCP connection or as a UDP datagram,
+// scenario sends it instead of httpRequest when set as tcpRequest or udpRequest.
+type SocketRequest struct {
+	// Address is host:port.
+	Address string `yaml:"address"`
+
+	// Payload is a text, can use ${var}.
+	Payload string `yaml:"payload"`
+	// PayloadHex is a binary payload, used instead of payload.
+	PayloadHex string `yaml:"payloadHex"`
+
+	// Response is read after payload is sent, nothing is read if not set.
+	Response *SocketResponse `yaml:"response"`
+
+	// Timeout of connecting, sending and reading response. Defaults to 5s.
+	TimeoutRaw time.Duration `yaml:"timeout"`
+}
+
+// SocketResponse is read until delimiter or length. If neither is set,
+// response is read until timeout or until connection is closed by server.
+type SocketResponse struct {
+	// Delimiter ends a response and is included in it, e.g. "\n".
+	Delimiter string `yaml:"delimiter"`
+	// Length is a number of bytes to read.
+	Length int `yaml:"length"`
+}
+
+func (r *SocketRequest) Timeout() time.Duration {
+	if r.TimeoutRaw > 0 {
+		return r.TimeoutRaw
+	}
+
+	return defaultSocketTimeout
+}
@@ -12,4 +12,7 @@ type Threshold struct {
 
 	MinRate *float64 `yaml:"minRate"`
 	MaxRate *float64 `yaml:"maxRate"`
+
+	// MaxIncrease is a relative threshold, validated against a baseline run.
+	MaxIncrease *Percent `yaml:"maxIncrease"`
 }
@@ -0,0 +1,54 @@
+package config
+
+import "time"
+
+const defaultWebSocketTimeout = 5 * time.Second
+
+// WebSocket opens a connection and runs a script of messages, scenario uses it instead of httpRequest when set.
+type WebSocket struct {
+	// URL with ws or wss scheme.
+	URL          string            `yaml:"url"`
+	Headers      map[string]string `yaml:"headers"`
+	Subprotocols []string          `yaml:"subprotocols"`
+	// TLS settings of wss connections, same as httpClient.tls.
+	TLS *TLS `yaml:"tls"`
+
+	// Connections is a number of connections held open for the scenario duration, opened at rps rate.
+	// Each connection repeats messages until the end. If not set, a connection is opened for every request,
+	// messages are sent once and connection is closed.
+	Connections int `yaml:"connections"`
+
+	Messages []*WebSocketMessage `yaml:"messages"`
+
+	// Timeout of opening a connection and waiting for replies. Defaults to 5s.
+	TimeoutRaw time.Duration `yaml:"timeout"`
+}
+
+// WebSocketMessage is a step of a script, it sends a message and waits for a reply if receive or checks are set.
+type WebSocketMessage struct {
+	// Pause before the step.
+	Pause time.Duration `yaml:"pause"`
+
+	Send string `yaml:"send"`
+	// Binary sends message as a binary frame.
+	Binary bool `yaml:"binary"`
+
+	// Receive waits for any message, messages which do not pass checks are skipped.
+	Receive bool     `yaml:"receive"`
+	Checks  []*Check `yaml:"checks"`
+	// Timeout of waiting for a reply, overrides websocket timeout.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+func (w *WebSocket) Timeout() time.Duration {
+	if w.TimeoutRaw > 0 {
+		return w.TimeoutRaw
+	}
+
+	return defaultWebSocketTimeout
+}
+
+// ExpectsReply reports whether step waits for a message from server.
+func (m *WebSocketMessage) ExpectsReply() bool {
+	return m.Receive || len(m.Checks) > 0
+}
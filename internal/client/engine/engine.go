@@ -0,0 +1,189 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/lameaux/bro/internal/client/runner"
+	"github.com/lameaux/bro/internal/client/stats"
+	"github.com/lameaux/bro/internal/client/thresholds"
+	"github.com/lameaux/bro/internal/shared/httpclient"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// Engine runs scenarios of a config and collects their stats, it is used by bro and pkg/bro.
+type Engine struct {
+	conf        *config.Config
+	listeners   []runner.StatListener
+	httpClients []*httpclient.Clients // by scenarioID
+}
+
+func New(conf *config.Config, listeners []runner.StatListener) (*Engine, error) {
+	e := &Engine{
+		conf:      conf,
+		listeners: listeners,
+	}
+
+	if err := e.setupHTTPClients(); err != nil {
+		return nil, fmt.Errorf("failed to create http client: %w", err)
+	}
+
+	return e, nil
+}
+
+// setupHTTPClients shares global http clients between scenarios unless scenario overrides its settings
+// or sends requests over a unix socket.
+func (e *Engine) setupHTTPClients() error {
+	shared, err := httpclient.NewClients(e.conf.HTTPClient)
+	if err != nil {
+		return fmt.Errorf("invalid httpClient: %w", err)
+	}
+
+	e.httpClients = make([]*httpclient.Clients, len(e.conf.Scenarios))
+
+	for scenarioID, scenario := range e.conf.Scenarios {
+		_, unixSocket := scenario.HTTPRequest.Target()
+
+		if scenario.HTTPClient == nil && unixSocket == "" {
+			e.httpClients[scenarioID] = shared
+
+			continue
+		}
+
+		conf := config.MergeHTTPClient(scenario.HTTPClient, e.conf.HTTPClient)
+		conf.UnixSocket = unixSocket
+
+		clients, err := httpclient.NewClients(conf)
+		if err != nil {
+			return fmt.Errorf("invalid httpClient of scenario %s: %w", scenario.Name, err)
+		}
+
+		e.httpClients[scenarioID] = clients
+	}
+
+	return nil
+}
+
+// Close closes http clients of scenarios, e.g. sockets of local addresses, when engine is no longer used.
+func (e *Engine) Close() error {
+	closed := make(map[*httpclient.Clients]bool, len(e.httpClients))
+
+	var errs []error
+
+	for _, clients := range e.httpClients {
+		if closed[clients] {
+			continue
+		}
+
+		closed[clients] = true
+
+		errs = append(errs, clients.Close())
+	}
+
+	return errors.Join(errs...)
+}
+
+// Run executes scenarios until they finish or ctx is canceled.
+// Scenarios which failed to start are missing in stats, their errors are joined.
+func (e *Engine) Run(ctx context.Context) (*stats.Stats, error) {
+	results := stats.New()
+	defer results.StopTimer()
+
+	if e.conf.Parallel {
+		return results, e.runParallel(ctx, results)
+	}
+
+	return results, e.runSerial(ctx, results)
+}
+
+func (e *Engine) runParallel(
+	ctx context.Context,
+	results *stats.Stats,
+) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+
+	wg.Add(len(e.conf.Scenarios))
+
+	for scenarioID, scenario := range e.conf.Scenarios {
+		go func(scenarioID int, scenario *config.Scenario) {
+			defer wg.Done()
+
+			if err := e.runScenario(ctx, scenarioID, scenario, results); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}(scenarioID, scenario)
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+func (e *Engine) runSerial(
+	ctx context.Context,
+	results *stats.Stats,
+) error {
+	var errs []error
+
+	for scenarioID, scenario := range e.conf.Scenarios {
+		if err := e.runScenario(ctx, scenarioID, scenario, results); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (e *Engine) runScenario(
+	ctx context.Context,
+	scenarioID int,
+	scenario *config.Scenario,
+	results *stats.Stats,
+) error {
+	localCounters := stats.NewCounters()
+	checkCounters := thresholds.NewCheckCounters()
+
+	listeners := append([]runner.StatListener{localCounters}, e.listeners...)
+
+	r := runner.New(e.httpClients[scenarioID], scenarioID, scenario, checkCounters, listeners)
+
+	startTime := time.Now()
+
+	err := r.Run(ctx)
+	if err != nil {
+		log.Error().Err(err).
+			Dict("scenario", zerolog.Dict().Str("name", scenario.Name)).
+			Msg("failed to run scenario")
+
+		return fmt.Errorf("failed to run scenario %s: %w", scenario.Name, err)
+	}
+
+	duration := time.Since(startTime).Round(time.Millisecond)
+
+	results.SetCounters(scenario.Name, localCounters)
+	results.SetDuration(scenario.Name, duration)
+
+	passed, err := thresholds.ValidateScenario(scenario, checkCounters, localCounters, duration)
+	if err != nil {
+		log.Warn().
+			Dict("scenario", zerolog.Dict().Str("name", scenario.Name)).
+			Msg("failed to validate thresholds")
+
+		return nil
+	}
+
+	results.SetThresholdsPassed(scenario.Name, passed)
+
+	return nil
+}
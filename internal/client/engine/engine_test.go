@@ -0,0 +1,39 @@
+package engine_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/lameaux/bro/internal/client/engine"
+)
+
+func TestRun_FailedScenario(t *testing.T) {
+	t.Parallel()
+
+	for _, parallel := range []bool{false, true} {
+		conf := &config.Config{
+			Name:      "test",
+			Parallel:  parallel,
+			Scenarios: []*config.Scenario{{Name: "unknown", TypeRaw: "unknown"}},
+		}
+
+		e, err := engine.New(conf, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		results, err := e.Run(context.Background())
+		if err == nil {
+			t.Errorf("parallel=%v: expected error of unknown scenario type", parallel)
+		}
+
+		if results.Counters("unknown") != nil {
+			t.Errorf("parallel=%v: expected no stats of failed scenario", parallel)
+		}
+
+		if err := e.Close(); err != nil {
+			t.Errorf("unexpected close error: %v", err)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package extractor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/lameaux/bro/internal/client/config"
+)
+
+const TypeCookie = "cookie"
+
+var (
+	ErrUnknownExtractorType = errors.New("unknown extractor type")
+
+	varPattern = regexp.MustCompile(`\$\{([A-Za-z0-9_.-]+)\}`)
+)
+
+type Extractor struct {
+	extractors []*config.Extractor
+}
+
+func New(extractors []*config.Extractor) *Extractor {
+	return &Extractor{
+		extractors: extractors,
+	}
+}
+
+// Extract returns values found in response by variable name, missing values are skipped.
+func (e *Extractor) Extract(response *http.Response) (map[string]string, error) {
+	values := make(map[string]string, len(e.extractors))
+
+	for _, extractor := range e.extractors {
+		value, found, err := RunExtractor(extractor, response)
+		if err != nil {
+			return nil, err
+		}
+
+		if found {
+			values[extractor.VarName()] = value
+		}
+	}
+
+	return values, nil
+}
+
+func RunExtractor(extractor *config.Extractor, response *http.Response) (string, bool, error) {
+	if extractor.Type == TypeCookie {
+		value, found := ExtractCookie(extractor, response)
+
+		return value, found, nil
+	}
+
+	return "", false, fmt.Errorf("%w: %s", ErrUnknownExtractorType, extractor.Type)
+}
+
+func ExtractCookie(extractor *config.Extractor, response *http.Response) (string, bool) {
+	for _, cookie := range response.Cookies() {
+		if cookie.Name == extractor.Name {
+			return cookie.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// Expand replaces ${var} with values of variables, unknown variables are left as is.
+func Expand(s string, vars map[string]string) string {
+	return expand(s, vars, func(value string) string { return value })
+}
+
+// ExpandJSON replaces ${var} inside JSON strings, values are escaped, e.g. quotes of a value do not break the JSON.
+func ExpandJSON(s string, vars map[string]string) string {
+	return expand(s, vars, func(value string) string {
+		var buf strings.Builder
+
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		_ = encoder.Encode(value)
+
+		// encoded string is quoted and followed by a newline
+		encoded := strings.TrimSpace(buf.String())
+
+		return encoded[1 : len(encoded)-1]
+	})
+}
+
+func expand(s string, vars map[string]string, escape func(string) string) string {
+	if len(vars) == 0 {
+		return s
+	}
+
+	return varPattern.ReplaceAllStringFunc(s, func(match string) string {
+		name := varPattern.FindStringSubmatch(match)[1]
+		if value, ok := vars[name]; ok {
+			return escape(value)
+		}
+
+		return match
+	})
+}
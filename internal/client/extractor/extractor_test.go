@@ -0,0 +1,75 @@
+package extractor_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/lameaux/bro/internal/client/extractor"
+)
+
+func TestExtractor_Extract(t *testing.T) {
+	t.Parallel()
+
+	resp := &http.Response{Header: http.Header{"Set-Cookie": []string{"session=abc123; Path=/", "csrf=xyz"}}}
+
+	values, err := extractor.New([]*config.Extractor{
+		{Type: extractor.TypeCookie, Name: "session"},
+		{Type: extractor.TypeCookie, Name: "csrf", Var: "token"},
+		{Type: extractor.TypeCookie, Name: "missing"},
+	}).Extract(resp)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]string{"session": "abc123", "token": "xyz"}
+	if len(values) != len(expected) {
+		t.Errorf("values equal %v; expected %v", values, expected)
+	}
+
+	for name, value := range expected {
+		if values[name] != value {
+			t.Errorf("%s equals %s; expected %s", name, values[name], value)
+		}
+	}
+
+	_, err = extractor.New([]*config.Extractor{{Type: "invalid"}}).Extract(resp)
+	if !errors.Is(err, extractor.ErrUnknownExtractorType) {
+		t.Errorf("err equals %v; expected %v", err, extractor.ErrUnknownExtractorType)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	t.Parallel()
+
+	vars := map[string]string{"session": "abc123"}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "/cart?s=${session}", expected: "/cart?s=abc123"},
+		{input: "/cart?s=${unknown}", expected: "/cart?s=${unknown}"},
+		{input: `{"price": "$10"}`, expected: `{"price": "$10"}`},
+	}
+
+	for _, tt := range tests {
+		if actual := extractor.Expand(tt.input, vars); actual != tt.expected {
+			t.Errorf("expand(%s) equals %s; expected %s", tt.input, actual, tt.expected)
+		}
+	}
+}
+
+func TestExpandJSON(t *testing.T) {
+	t.Parallel()
+
+	vars := map[string]string{"name": "say \"hi\" <b>\n"}
+
+	actual := extractor.ExpandJSON(`{"name": "${name}", "other": "${unknown}"}`, vars)
+	expected := `{"name": "say \"hi\" <b>\n", "other": "${unknown}"}`
+
+	if actual != expected {
+		t.Errorf("expandJSON equals %s; expected %s", actual, expected)
+	}
+}
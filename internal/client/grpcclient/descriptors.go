@@ -0,0 +1,121 @@
+package grpcclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bufbuild/protocompile"
+	"github.com/lameaux/bro/internal/client/config"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+var (
+	errInvalidMethod   = errors.New("method must be in the form of package.Service/Method")
+	errServiceNotFound = errors.New("service not found")
+	errMethodNotFound  = errors.New("method not found")
+)
+
+// splitMethod accepts package.Service/Method, /package.Service/Method and package.Service.Method.
+func splitMethod(name string) (string, string, error) {
+	name = strings.TrimPrefix(name, "/")
+
+	service, method, ok := strings.Cut(name, "/")
+	if !ok {
+		pos := strings.LastIndex(name, ".")
+		if pos < 0 {
+			return "", "", errInvalidMethod
+		}
+
+		service, method = name[:pos], name[pos+1:]
+	}
+
+	if service == "" || method == "" {
+		return "", "", errInvalidMethod
+	}
+
+	return service, method, nil
+}
+
+func loadMethod(
+	ctx context.Context,
+	conn *grpc.ClientConn,
+	conf *config.GRPCRequest,
+) (protoreflect.MethodDescriptor, error) {
+	serviceName, methodName, err := splitMethod(conf.Method)
+	if err != nil {
+		return nil, err
+	}
+
+	var resolver protodesc.Resolver
+
+	switch {
+	case conf.Protoset != "":
+		resolver, err = loadProtoset(conf.Protoset)
+	case len(conf.ProtoFiles) > 0:
+		resolver, err = compileProtoFiles(ctx, conf.ProtoFiles, conf.ImportPaths)
+	default:
+		resolver, err = reflectService(ctx, conn, serviceName)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	desc, err := resolver.FindDescriptorByName(protoreflect.FullName(serviceName))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errServiceNotFound, serviceName)
+	}
+
+	service, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errServiceNotFound, serviceName)
+	}
+
+	method := service.Methods().ByName(protoreflect.Name(methodName))
+	if method == nil {
+		return nil, fmt.Errorf("%w: %s/%s", errMethodNotFound, serviceName, methodName)
+	}
+
+	return method, nil
+}
+
+func loadProtoset(fileName string) (protodesc.Resolver, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read protoset: %w", err)
+	}
+
+	var set descriptorpb.FileDescriptorSet
+	if err = proto.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse protoset: %w", err)
+	}
+
+	files, err := protodesc.NewFiles(&set)
+	if err != nil {
+		return nil, fmt.Errorf("invalid protoset: %w", err)
+	}
+
+	return files, nil
+}
+
+func compileProtoFiles(ctx context.Context, fileNames []string, importPaths []string) (protodesc.Resolver, error) {
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			ImportPaths: importPaths,
+		}),
+	}
+
+	files, err := compiler.Compile(ctx, fileNames...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile proto files: %w", err)
+	}
+
+	return files.AsResolver(), nil
+}
@@ -0,0 +1,229 @@
+package grpcclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/lameaux/bro/internal/shared/httpclient"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+var (
+	errMissingTarget         = errors.New("missing target")
+	errClientStreaming       = errors.New("client and bidirectional streaming calls are not supported")
+	errInvalidRequestMessage = errors.New("invalid request message")
+)
+
+// Invoker sends a gRPC request described by config using dynamic messages.
+type Invoker struct {
+	conn       *grpc.ClientConn
+	method     protoreflect.MethodDescriptor
+	fullMethod string
+	request    proto.Message
+	metadata   metadata.MD
+	conf       *config.GRPCRequest
+}
+
+// Response contains status and messages of a call, server-streaming calls can return many messages.
+type Response struct {
+	Code          codes.Code
+	StatusMessage string
+	Messages      []proto.Message
+	Header        metadata.MD
+	Trailer       metadata.MD
+
+	SentBytes     int64
+	ReceivedBytes int64
+}
+
+// Failed reports whether call has not reached the server or has not completed in time.
+// Such statuses are tracked as transport errors, the rest are validated by checks.
+func (r *Response) Failed() bool {
+	switch r.Code { //nolint:exhaustive
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled:
+		return true
+	default:
+		return false
+	}
+}
+
+// Err returns status error of a failed call.
+func (r *Response) Err() error {
+	return status.Error(r.Code, r.StatusMessage) //nolint:wrapcheck
+}
+
+// NewInvoker connects to target and resolves method descriptor from proto files, protoset or server reflection.
+func NewInvoker(ctx context.Context, conf *config.GRPCRequest) (*Invoker, error) {
+	if conf.Target == "" {
+		return nil, errMissingTarget
+	}
+
+	creds := insecure.NewCredentials()
+
+	if conf.TLS != nil {
+		tlsConfig, err := httpclient.NewTLSConfig(*conf.TLS)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tls: %w", err)
+		}
+
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	conn, err := grpc.NewClient(conf.Target, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create grpc client: %w", err)
+	}
+
+	invoker, err := newInvoker(ctx, conn, conf)
+	if err != nil {
+		_ = conn.Close()
+
+		return nil, err
+	}
+
+	return invoker, nil
+}
+
+func newInvoker(ctx context.Context, conn *grpc.ClientConn, conf *config.GRPCRequest) (*Invoker, error) {
+	method, err := loadMethod(ctx, conn, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	if method.IsStreamingClient() {
+		return nil, errClientStreaming
+	}
+
+	request := dynamicpb.NewMessage(method.Input())
+
+	if conf.Message != "" {
+		if err = protojson.Unmarshal([]byte(conf.Message), request); err != nil {
+			return nil, fmt.Errorf("%w: %w", errInvalidRequestMessage, err)
+		}
+	}
+
+	return &Invoker{
+		conn:       conn,
+		method:     method,
+		fullMethod: fmt.Sprintf("/%s/%s", method.Parent().FullName(), method.Name()),
+		request:    request,
+		metadata:   metadata.New(conf.Metadata),
+		conf:       conf,
+	}, nil
+}
+
+func (i *Invoker) Close() error {
+	return i.conn.Close() //nolint:wrapcheck
+}
+
+// Method returns full name of the method, e.g. /package.Service/Method.
+func (i *Invoker) Method() string {
+	return i.fullMethod
+}
+
+// Streaming reports whether method is a server-streaming call.
+func (i *Invoker) Streaming() bool {
+	return i.method.IsStreamingServer()
+}
+
+// Call sends request and waits for all response messages. gRPC status is returned as part of response.
+func (i *Invoker) Call(ctx context.Context) *Response {
+	if i.conf.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, i.conf.Timeout)
+		defer cancel()
+	}
+
+	if len(i.metadata) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, i.metadata)
+	}
+
+	resp := &Response{
+		SentBytes: int64(proto.Size(i.request)),
+	}
+
+	var err error
+
+	if i.Streaming() {
+		err = i.callStreaming(ctx, resp)
+	} else {
+		err = i.callUnary(ctx, resp)
+	}
+
+	for _, msg := range resp.Messages {
+		resp.ReceivedBytes += int64(proto.Size(msg))
+	}
+
+	st := status.Convert(err)
+	resp.Code = st.Code()
+	resp.StatusMessage = st.Message()
+
+	return resp
+}
+
+func (i *Invoker) callUnary(ctx context.Context, resp *Response) error {
+	msg := dynamicpb.NewMessage(i.method.Output())
+
+	err := i.conn.Invoke(ctx, i.fullMethod, i.request, msg, grpc.Header(&resp.Header), grpc.Trailer(&resp.Trailer))
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	resp.Messages = append(resp.Messages, msg)
+
+	return nil
+}
+
+func (i *Invoker) callStreaming(ctx context.Context, resp *Response) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := i.conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, i.fullMethod)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if err = stream.SendMsg(i.request); err != nil && !errors.Is(err, io.EOF) {
+		return err //nolint:wrapcheck
+	}
+
+	if err = stream.CloseSend(); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	for {
+		msg := dynamicpb.NewMessage(i.method.Output())
+
+		err = stream.RecvMsg(msg)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			resp.Header, _ = stream.Header()
+			resp.Trailer = stream.Trailer()
+
+			return err //nolint:wrapcheck
+		}
+
+		resp.Messages = append(resp.Messages, msg)
+	}
+
+	resp.Header, _ = stream.Header()
+	resp.Trailer = stream.Trailer()
+
+	return nil
+}
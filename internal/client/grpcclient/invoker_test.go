@@ -0,0 +1,378 @@
+package grpcclient_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bufbuild/protocompile"
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/lameaux/bro/internal/client/grpcclient"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const echoService = "bro.test.Echo"
+
+var errDescriptorNotFound = errors.New("descriptor not found")
+
+func compileEcho(t *testing.T) protoreflect.FileDescriptor {
+	t.Helper()
+
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			ImportPaths: []string{"testdata"},
+		}),
+	}
+
+	files, err := compiler.Compile(context.Background(), "echo.proto")
+	if err != nil {
+		t.Fatalf("failed to compile proto: %v", err)
+	}
+
+	return files[0]
+}
+
+// startEchoServer serves Echo service using dynamic messages, Say fails with NotFound for "missing" text.
+func startEchoServer(t *testing.T) string {
+	t.Helper()
+
+	file := compileEcho(t)
+	service := file.Services().ByName("Echo")
+	input := service.Methods().ByName("Say").Input()
+	output := service.Methods().ByName("Say").Output()
+
+	reply := func(req *dynamicpb.Message, index int) *dynamicpb.Message {
+		resp := dynamicpb.NewMessage(output)
+		resp.Set(output.Fields().ByName("text"), req.Get(input.Fields().ByName("text")))
+		resp.Set(output.Fields().ByName("index"), protoreflect.ValueOfInt32(int32(index)))
+
+		return resp
+	}
+
+	desc := &grpc.ServiceDesc{
+		ServiceName: echoService,
+		HandlerType: (*any)(nil),
+		Methods: []grpc.MethodDesc{
+			{
+				MethodName: "Say",
+				Handler: func(_ any, ctx context.Context, dec func(any) error, _ grpc.UnaryServerInterceptor) (any, error) {
+					req := dynamicpb.NewMessage(input)
+					if err := dec(req); err != nil {
+						return nil, err
+					}
+
+					if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get("x-fail")) > 0 {
+						return nil, status.Error(codes.PermissionDenied, "denied")
+					}
+
+					if req.Get(input.Fields().ByName("text")).String() == "missing" {
+						return nil, status.Error(codes.NotFound, "not found")
+					}
+
+					return reply(req, 0), nil
+				},
+			},
+		},
+		Streams: []grpc.StreamDesc{
+			{
+				StreamName:    "Repeat",
+				ServerStreams: true,
+				Handler: func(_ any, stream grpc.ServerStream) error {
+					req := dynamicpb.NewMessage(input)
+					if err := stream.RecvMsg(req); err != nil {
+						return err
+					}
+
+					count := int(req.Get(input.Fields().ByName("count")).Int())
+					for i := 0; i < count; i++ {
+						if err := stream.SendMsg(reply(req, i)); err != nil {
+							return err
+						}
+					}
+
+					return nil
+				},
+			},
+		},
+	}
+
+	server := grpc.NewServer()
+	server.RegisterService(desc, struct{}{})
+
+	files := &echoResolver{file: file}
+	reflectionpb.RegisterServerReflectionServer(server, reflection.NewServerV1(reflection.ServerOptions{
+		Services:           server,
+		DescriptorResolver: files,
+	}))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String()
+}
+
+// echoResolver resolves descriptors of echo.proto and its imports for reflection server.
+type echoResolver struct {
+	file protoreflect.FileDescriptor
+}
+
+func (r *echoResolver) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	if path == r.file.Path() {
+		return r.file, nil
+	}
+
+	imports := r.file.Imports()
+	for i := 0; i < imports.Len(); i++ {
+		if imports.Get(i).Path() == path {
+			return imports.Get(i).FileDescriptor, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", errDescriptorNotFound, path)
+}
+
+func (r *echoResolver) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	if desc := r.file.Services().ByName(name.Name()); desc != nil && desc.FullName() == name {
+		return desc, nil
+	}
+
+	if desc := r.file.Messages().ByName(name.Name()); desc != nil && desc.FullName() == name {
+		return desc, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", errDescriptorNotFound, name)
+}
+
+func writeProtoset(t *testing.T) string {
+	t.Helper()
+
+	file := compileEcho(t)
+
+	set := &descriptorpb.FileDescriptorSet{}
+
+	imports := file.Imports()
+	for i := 0; i < imports.Len(); i++ {
+		set.File = append(set.File, protodesc.ToFileDescriptorProto(imports.Get(i).FileDescriptor))
+	}
+
+	set.File = append(set.File, protodesc.ToFileDescriptorProto(file))
+
+	data, err := proto.Marshal(set)
+	if err != nil {
+		t.Fatalf("failed to marshal protoset: %v", err)
+	}
+
+	fileName := filepath.Join(t.TempDir(), "echo.protoset")
+	if err = os.WriteFile(fileName, data, 0o600); err != nil {
+		t.Fatalf("failed to write protoset: %v", err)
+	}
+
+	return fileName
+}
+
+func TestInvoker_Call(t *testing.T) {
+	t.Parallel()
+
+	target := startEchoServer(t)
+	protoset := writeProtoset(t)
+
+	tests := []struct {
+		name     string
+		conf     config.GRPCRequest
+		code     codes.Code
+		messages int
+		failed   bool
+	}{
+		{
+			name: "unary with proto files",
+			conf: config.GRPCRequest{
+				Method:      echoService + "/Say",
+				Message:     `{"text": "hello"}`,
+				ProtoFiles:  []string{"echo.proto"},
+				ImportPaths: []string{"testdata"},
+			},
+			code:     codes.OK,
+			messages: 1,
+		},
+		{
+			name: "unary with status",
+			conf: config.GRPCRequest{
+				Method:   echoService + "/Say",
+				Message:  `{"text": "missing"}`,
+				Protoset: protoset,
+			},
+			code: codes.NotFound,
+		},
+		{
+			name: "metadata",
+			conf: config.GRPCRequest{
+				Method:   echoService + "/Say",
+				Metadata: map[string]string{"x-fail": "1"},
+				Protoset: protoset,
+			},
+			code: codes.PermissionDenied,
+		},
+		{
+			name: "server streaming with protoset",
+			conf: config.GRPCRequest{
+				Method:   echoService + "/Repeat",
+				Message:  `{"text": "hello", "count": 3}`,
+				Protoset: protoset,
+			},
+			code:     codes.OK,
+			messages: 3,
+		},
+		{
+			name: "server streaming with reflection",
+			conf: config.GRPCRequest{
+				Method:  echoService + ".Repeat",
+				Message: `{"text": "hello", "count": 2}`,
+			},
+			code:     codes.OK,
+			messages: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			conf := tt.conf
+			conf.Target = target
+
+			invoker, err := grpcclient.NewInvoker(context.Background(), &conf)
+			if err != nil {
+				t.Fatalf("failed to create invoker: %v", err)
+			}
+			defer invoker.Close()
+
+			resp := invoker.Call(context.Background())
+
+			if resp.Code != tt.code {
+				t.Fatalf("code equals %v; expected %v: %s", resp.Code, tt.code, resp.StatusMessage)
+			}
+
+			if len(resp.Messages) != tt.messages {
+				t.Errorf("got %d messages; expected %d", len(resp.Messages), tt.messages)
+			}
+
+			if resp.Failed() != tt.failed {
+				t.Errorf("failed equals %v; expected %v", resp.Failed(), tt.failed)
+			}
+
+			if tt.messages > 0 && resp.ReceivedBytes == 0 {
+				t.Errorf("received bytes are not counted")
+			}
+		})
+	}
+}
+
+func TestInvoker_Unavailable(t *testing.T) {
+	t.Parallel()
+
+	invoker, err := grpcclient.NewInvoker(context.Background(), &config.GRPCRequest{
+		Target:      "127.0.0.1:1",
+		Method:      echoService + "/Say",
+		ProtoFiles:  []string{"echo.proto"},
+		ImportPaths: []string{"testdata"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create invoker: %v", err)
+	}
+	defer invoker.Close()
+
+	resp := invoker.Call(context.Background())
+
+	if !resp.Failed() {
+		t.Fatalf("code equals %v; expected failed call", resp.Code)
+	}
+
+	if !strings.Contains(resp.Err().Error(), "connection refused") {
+		t.Errorf("unexpected error: %v", resp.Err())
+	}
+}
+
+func TestNewInvoker_Errors(t *testing.T) {
+	t.Parallel()
+
+	target := startEchoServer(t)
+
+	tests := []struct {
+		name string
+		conf config.GRPCRequest
+		err  string
+	}{
+		{
+			name: "invalid method",
+			conf: config.GRPCRequest{Method: "Say"},
+			err:  "method must be",
+		},
+		{
+			name: "unknown method",
+			conf: config.GRPCRequest{Method: echoService + "/Unknown"},
+			err:  "method not found",
+		},
+		{
+			name: "unknown service",
+			conf: config.GRPCRequest{Method: "bro.test.Unknown/Say", ProtoFiles: []string{"echo.proto"}, ImportPaths: []string{"testdata"}},
+			err:  "service not found",
+		},
+		{
+			name: "client streaming",
+			conf: config.GRPCRequest{Method: echoService + "/Collect"},
+			err:  "not supported",
+		},
+		{
+			name: "invalid message",
+			conf: config.GRPCRequest{Method: echoService + "/Say", Message: `{"unknown": 1}`},
+			err:  "invalid request message",
+		},
+		{
+			name: "missing proto file",
+			conf: config.GRPCRequest{Method: echoService + "/Say", ProtoFiles: []string{"missing.proto"}},
+			err:  "failed to compile",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			conf := tt.conf
+			conf.Target = target
+
+			invoker, err := grpcclient.NewInvoker(context.Background(), &conf)
+			if err == nil {
+				invoker.Close()
+				t.Fatalf("expected error")
+			}
+
+			if !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("error %q does not contain %q", err, tt.err)
+			}
+		})
+	}
+}
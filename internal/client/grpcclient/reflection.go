@@ -0,0 +1,117 @@
+package grpcclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+var errReflection = errors.New("server reflection failed")
+
+// reflectService downloads the file containing service and all its dependencies using server reflection.
+func reflectService(ctx context.Context, conn *grpc.ClientConn, service string) (protodesc.Resolver, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errReflection, err)
+	}
+
+	files := make(map[string]*descriptorpb.FileDescriptorProto)
+
+	fileProtos, err := reflectFiles(stream, &reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: service},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// servers may omit dependencies which are requested one by one
+	for len(fileProtos) > 0 {
+		var missing []string
+
+		for _, file := range fileProtos {
+			files[file.GetName()] = file
+		}
+
+		for _, file := range fileProtos {
+			for _, dependency := range file.GetDependency() {
+				if _, ok := files[dependency]; !ok {
+					missing = append(missing, dependency)
+				}
+			}
+		}
+
+		fileProtos = nil
+
+		for _, fileName := range missing {
+			if _, ok := files[fileName]; ok {
+				continue
+			}
+
+			dependencies, err := reflectFiles(stream, &reflectionpb.ServerReflectionRequest{
+				MessageRequest: &reflectionpb.ServerReflectionRequest_FileByFilename{FileByFilename: fileName},
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			for _, dependency := range dependencies {
+				files[dependency.GetName()] = dependency
+			}
+
+			fileProtos = append(fileProtos, dependencies...)
+		}
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	for _, file := range files {
+		set.File = append(set.File, file)
+	}
+
+	resolver, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errReflection, err)
+	}
+
+	return resolver, nil
+}
+
+func reflectFiles(
+	stream reflectionpb.ServerReflection_ServerReflectionInfoClient,
+	req *reflectionpb.ServerReflectionRequest,
+) ([]*descriptorpb.FileDescriptorProto, error) {
+	if err := stream.Send(req); err != nil {
+		return nil, fmt.Errorf("%w: %w", errReflection, err)
+	}
+
+	resp, err := stream.Recv()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errReflection, err)
+	}
+
+	if errResp := resp.GetErrorResponse(); errResp != nil {
+		return nil, fmt.Errorf("%w: %s", errReflection, errResp.GetErrorMessage())
+	}
+
+	encoded := resp.GetFileDescriptorResponse().GetFileDescriptorProto()
+	files := make([]*descriptorpb.FileDescriptorProto, 0, len(encoded))
+
+	for _, data := range encoded {
+		file := &descriptorpb.FileDescriptorProto{}
+		if err = proto.Unmarshal(data, file); err != nil {
+			return nil, fmt.Errorf("%w: %w", errReflection, err)
+		}
+
+		files = append(files, file)
+	}
+
+	return files, nil
+}
@@ -0,0 +1,337 @@
+package live
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/lameaux/bro/internal/client/tracking"
+	"github.com/mattn/go-isatty"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	refreshInterval  = time.Second
+	progressInterval = 5 * time.Second
+
+	// latency percentiles and error rate are calculated over the last windowSize seconds.
+	windowSize  = 10
+	historySize = 30
+)
+
+//nolint:gochecknoglobals
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// Dashboard shows progress of running scenarios.
+// It renders a table on terminal, or writes periodic progress logs when output is not a terminal.
+type Dashboard struct {
+	out *os.File
+	tty bool
+
+	mu        sync.Mutex
+	scenarios []*scenarioState
+	lines     int
+}
+
+type scenarioState struct {
+	name string
+
+	stage      tracking.StageInfo
+	stageStart time.Time
+
+	inFlight int64
+
+	current  window
+	windows  []window
+	history  []int64
+	latency  *hdrhistogram.WindowedHistogram
+	achieved int64
+}
+
+type window struct {
+	total  int64
+	failed int64
+}
+
+func New(out *os.File) *Dashboard {
+	return &Dashboard{
+		out: out,
+		tty: isatty.IsTerminal(out.Fd()) || isatty.IsCygwinTerminal(out.Fd()),
+	}
+}
+
+// IsTerminal reports whether the dashboard is rendered as a table.
+func (d *Dashboard) IsTerminal() bool {
+	return d.tty
+}
+
+func (d *Dashboard) Run(ctx context.Context) {
+	refreshTicker := time.NewTicker(refreshInterval)
+	defer refreshTicker.Stop()
+
+	var elapsed time.Duration
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-refreshTicker.C:
+			d.rotate()
+
+			if d.tty {
+				d.render()
+
+				continue
+			}
+
+			elapsed += refreshInterval
+			if elapsed%progressInterval == 0 {
+				d.logProgress()
+			}
+		}
+	}
+}
+
+// Stop renders the final state of scenarios.
+func (d *Dashboard) Stop() {
+	if d.tty {
+		d.render()
+	}
+}
+
+func (d *Dashboard) scenario(name string) *scenarioState {
+	for _, state := range d.scenarios {
+		if state.name == name {
+			return state
+		}
+	}
+
+	state := &scenarioState{
+		name:    name,
+		latency: hdrhistogram.NewWindowed(windowSize, 1, 1e6, 3), //nolint:mnd,gomnd
+	}
+	d.scenarios = append(d.scenarios, state)
+
+	return state
+}
+
+func (d *Dashboard) TrackStage(info *tracking.StageInfo) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	state := d.scenario(info.Scenario)
+	state.stage = *info
+	state.stageStart = time.Now()
+}
+
+func (d *Dashboard) TrackRequest(info *tracking.RequestInfo) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.scenario(info.Scenario).inFlight++
+}
+
+func (d *Dashboard) TrackFailed(info *tracking.RequestInfo, _ error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	state := d.scenario(info.Scenario)
+	state.inFlight--
+	state.current.total++
+	state.current.failed++
+}
+
+func (d *Dashboard) TrackResponse(info *tracking.RequestInfo, success bool, latency time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	state := d.scenario(info.Scenario)
+	state.inFlight--
+	state.current.total++
+
+	if !success {
+		state.current.failed++
+	}
+
+	_ = state.latency.Current.RecordValue(latency.Milliseconds())
+}
+
+func (d *Dashboard) rotate() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, state := range d.scenarios {
+		state.achieved = state.current.total
+
+		state.windows = appendLimited(state.windows, state.current, windowSize)
+		state.history = appendLimited(state.history, state.current.total, historySize)
+		state.current = window{}
+
+		state.latency.Rotate()
+	}
+}
+
+type row struct {
+	name      string
+	stage     string
+	targetRPS int
+	rps       int64
+	inFlight  int64
+	p50       int64
+	p95       int64
+	p99       int64
+	errorRate float64
+	sparkline string
+}
+
+func (d *Dashboard) rows() []row {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	rows := make([]row, 0, len(d.scenarios))
+
+	for _, state := range d.scenarios {
+		latency := state.latency.Merge()
+
+		stage := state.stage.Stage
+		if stage == "" {
+			stage = "constant"
+		}
+
+		rows = append(rows, row{
+			name:      state.name,
+			stage:     stage,
+			targetRPS: state.targetRPS(time.Now()),
+			rps:       state.achieved,
+			inFlight:  state.inFlight,
+			p50:       latency.ValueAtPercentile(50), //nolint:mnd
+			p95:       latency.ValueAtPercentile(95), //nolint:mnd
+			p99:       latency.ValueAtPercentile(99), //nolint:mnd
+			errorRate: state.errorRate(),
+			sparkline: sparkline(state.history),
+		})
+	}
+
+	return rows
+}
+
+func (d *Dashboard) render() {
+	tableWriter := table.NewWriter()
+	tableWriter.AppendHeader(table.Row{
+		"Scenario", "Stage", "Target RPS", "RPS", "In-flight", "P50", "P95", "P99", "Errors", "RPS history",
+	})
+
+	for _, r := range d.rows() {
+		tableWriter.AppendRow(table.Row{
+			r.name,
+			r.stage,
+			r.targetRPS,
+			r.rps,
+			r.inFlight,
+			fmt.Sprintf("%d ms", r.p50),
+			fmt.Sprintf("%d ms", r.p95),
+			fmt.Sprintf("%d ms", r.p99),
+			fmt.Sprintf("%.2f%%", r.errorRate*100), //nolint:mnd
+			r.sparkline,
+		})
+	}
+
+	tableWriter.SetStyle(table.StyleLight)
+
+	output := tableWriter.Render()
+
+	var screen strings.Builder
+
+	if d.lines > 0 {
+		// move cursor to the beginning of previous output and clear it
+		screen.WriteString(fmt.Sprintf("\033[%dA\033[J", d.lines))
+	}
+
+	screen.WriteString(output)
+	screen.WriteString("\n")
+
+	d.lines = strings.Count(output, "\n") + 1
+
+	_, _ = d.out.WriteString(screen.String())
+}
+
+func (d *Dashboard) logProgress() {
+	for _, r := range d.rows() {
+		log.Info().
+			Dict("scenario", zerolog.Dict().Str("name", r.name)).
+			Str("stage", r.stage).
+			Int("targetRPS", r.targetRPS).
+			Int64("rps", r.rps).
+			Int64("inFlight", r.inFlight).
+			Int64("p50", r.p50).
+			Int64("p95", r.p95).
+			Int64("p99", r.p99).
+			Float64("errorRate", r.errorRate).
+			Msg("progress")
+	}
+}
+
+// targetRPS ramps from start to target rate of the stage, it stays at target rate when the stage is over,
+// e.g. until the next stage is tracked.
+func (s *scenarioState) targetRPS(now time.Time) int {
+	elapsed := now.Sub(s.stageStart)
+	if s.stage.Duration <= 0 || elapsed >= s.stage.Duration {
+		return s.stage.TargetRPS
+	}
+
+	step := float64(s.stage.TargetRPS-s.stage.StartRPS) * (elapsed.Seconds() / s.stage.Duration.Seconds())
+
+	return s.stage.StartRPS + int(step)
+}
+
+func (s *scenarioState) errorRate() float64 {
+	var total, failed int64
+
+	for _, w := range s.windows {
+		total += w.total
+		failed += w.failed
+	}
+
+	if total == 0 {
+		return 0
+	}
+
+	return float64(failed) / float64(total)
+}
+
+func sparkline(values []int64) string {
+	var maxValue int64
+
+	for _, value := range values {
+		maxValue = max(maxValue, value)
+	}
+
+	var output strings.Builder
+
+	for _, value := range values {
+		idx := 0
+		if maxValue > 0 {
+			idx = int(value * int64(len(sparks)-1) / maxValue)
+		}
+
+		output.WriteRune(sparks[idx])
+	}
+
+	return output.String()
+}
+
+func appendLimited[T any](values []T, value T, limit int) []T {
+	values = append(values, value)
+
+	if len(values) > limit {
+		values = values[len(values)-limit:]
+	}
+
+	return values
+}
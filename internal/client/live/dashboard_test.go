@@ -0,0 +1,218 @@
+package live
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lameaux/bro/internal/client/tracking"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+func newTestDashboard(t *testing.T) *Dashboard {
+	t.Helper()
+
+	out, err := os.Create(filepath.Join(t.TempDir(), "dashboard.txt"))
+	if err != nil {
+		t.Fatalf("failed to create output: %v", err)
+	}
+
+	t.Cleanup(func() { _ = out.Close() })
+
+	return New(out)
+}
+
+func TestRotate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		responses []bool // success of responses per rotation
+		rotations int
+		achieved  int64
+		windows   int
+		history   int
+	}{
+		{name: "single", responses: []bool{true, false}, rotations: 1, achieved: 2, windows: 1, history: 1},
+		{name: "idle", rotations: 3, achieved: 0, windows: 3, history: 3},
+		{name: "limited", responses: []bool{true}, rotations: 40, achieved: 1, windows: windowSize, history: historySize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			d := newTestDashboard(t)
+			d.TrackStage(&tracking.StageInfo{Scenario: "test"})
+
+			for range tt.rotations {
+				for _, success := range tt.responses {
+					info := &tracking.RequestInfo{Scenario: "test"}
+					d.TrackRequest(info)
+					d.TrackResponse(info, success, time.Millisecond)
+				}
+
+				d.rotate()
+			}
+
+			state := d.scenario("test")
+
+			if state.achieved != tt.achieved {
+				t.Errorf("achieved equals %d; expected %d", state.achieved, tt.achieved)
+			}
+
+			if len(state.windows) != tt.windows || len(state.history) != tt.history {
+				t.Errorf("got %d windows and %d history; expected %d and %d",
+					len(state.windows), len(state.history), tt.windows, tt.history)
+			}
+
+			if state.current != (window{}) || state.inFlight != 0 {
+				t.Errorf("unexpected current window %+v, in-flight %d", state.current, state.inFlight)
+			}
+		})
+	}
+}
+
+func TestErrorRate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		windows  []window
+		expected float64
+	}{
+		{name: "no windows", expected: 0},
+		{name: "no requests", windows: []window{{}, {}}, expected: 0},
+		{name: "no errors", windows: []window{{total: 10}}, expected: 0},
+		{name: "all windows", windows: []window{{total: 10, failed: 5}, {total: 30, failed: 5}}, expected: 0.25},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			state := &scenarioState{windows: tt.windows}
+			if rate := state.errorRate(); rate != tt.expected {
+				t.Errorf("error rate equals %v; expected %v", rate, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTargetRPS(t *testing.T) {
+	t.Parallel()
+
+	start := time.Now()
+
+	tests := []struct {
+		name     string
+		stage    tracking.StageInfo
+		elapsed  time.Duration
+		expected int
+	}{
+		{name: "no stage", expected: 0},
+		{name: "constant", stage: tracking.StageInfo{TargetRPS: 50}, elapsed: time.Minute, expected: 50},
+		{
+			name:     "ramp start",
+			stage:    tracking.StageInfo{StartRPS: 10, TargetRPS: 110, Duration: 10 * time.Second},
+			expected: 10,
+		},
+		{
+			name:     "ramp up",
+			stage:    tracking.StageInfo{StartRPS: 10, TargetRPS: 110, Duration: 10 * time.Second},
+			elapsed:  5 * time.Second,
+			expected: 60,
+		},
+		{
+			name:     "ramp down",
+			stage:    tracking.StageInfo{StartRPS: 100, TargetRPS: 0, Duration: 10 * time.Second},
+			elapsed:  2 * time.Second,
+			expected: 80,
+		},
+		{
+			name:     "stage over",
+			stage:    tracking.StageInfo{StartRPS: 10, TargetRPS: 110, Duration: 10 * time.Second},
+			elapsed:  11 * time.Second,
+			expected: 110,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			state := &scenarioState{stage: tt.stage, stageStart: start}
+			if rps := state.targetRPS(start.Add(tt.elapsed)); rps != tt.expected {
+				t.Errorf("target rps equals %d; expected %d", rps, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		values   []int64
+		expected string
+	}{
+		{name: "empty", expected: ""},
+		{name: "zeros", values: []int64{0, 0}, expected: "▁▁"},
+		{name: "constant", values: []int64{5, 5}, expected: "██"},
+		{name: "increasing", values: []int64{0, 1, 2, 3, 4, 5, 6, 7}, expected: "▁▂▃▄▅▆▇█"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if actual := sparkline(tt.values); actual != tt.expected {
+				t.Errorf("sparkline equals %q; expected %q", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLogProgress(t *testing.T) { //nolint:paralleltest // replaces global logger
+	var buf bytes.Buffer
+
+	logger := log.Logger
+	log.Logger = zerolog.New(&buf)
+
+	t.Cleanup(func() { log.Logger = logger })
+
+	d := newTestDashboard(t)
+	if d.IsTerminal() {
+		t.Fatal("expected file output not to be a terminal")
+	}
+
+	d.TrackStage(&tracking.StageInfo{Scenario: "test", Stage: "ramp", TargetRPS: 20})
+
+	info := &tracking.RequestInfo{Scenario: "test"}
+	d.TrackRequest(info)
+	d.TrackFailed(info, nil)
+	d.rotate()
+
+	d.logProgress()
+
+	output := buf.String()
+	for _, expected := range []string{
+		`"message":"progress"`, `"name":"test"`, `"stage":"ramp"`, `"targetRPS":20`, `"rps":1`, `"errorRate":1`,
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("progress %s does not contain %s", output, expected)
+		}
+	}
+
+	// progress is logged instead of rendered table
+	d.Stop()
+
+	if data, _ := os.ReadFile(d.out.Name()); len(data) != 0 {
+		t.Errorf("unexpected output: %s", data)
+	}
+}
@@ -0,0 +1,222 @@
+package report
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/lameaux/bro/internal/client/config"
+)
+
+const (
+	MetricTotal   = "total"
+	MetricSuccess = "success"
+	MetricFailed  = "failed"
+	MetricTimeout = "timeout"
+	MetricInvalid = "invalid"
+	MetricRPS     = "rps"
+	MetricLatency = "latency"
+	MetricErrors  = "errors"
+	MetricCodes   = "codes"
+
+	MetricBytes      = "bytes"
+	MetricThroughput = "throughput"
+	MetricCustom     = "custom"
+)
+
+//nolint:gochecknoglobals
+var defaultMetrics = []string{
+	MetricRPS,
+	MetricFailed,
+	MetricLatency + ".p50",
+	MetricLatency + ".p95",
+	MetricLatency + ".p99",
+}
+
+// Rule is a relative threshold applied to a metric when comparing two runs.
+type Rule struct {
+	Metric      string         `json:"metric"`
+	MaxIncrease config.Percent `json:"maxIncrease"`
+}
+
+type Diff struct {
+	Scenario    string          `json:"scenario"`
+	Metric      string          `json:"metric"`
+	Baseline    float64         `json:"baseline"`
+	Current     float64         `json:"current"`
+	Change      *float64        `json:"change,omitempty"` // nil when baseline is zero
+	MaxIncrease *config.Percent `json:"maxIncrease,omitempty"`
+	Passed      bool            `json:"passed"`
+	// Error is set when a metric with relative threshold is missing in a report, the diff fails then.
+	Error string `json:"error,omitempty"`
+}
+
+type Comparison struct {
+	Baseline string  `json:"baseline,omitempty"`
+	Passed   bool    `json:"passed"`
+	Diffs    []*Diff `json:"diffs"`
+}
+
+// MetricKey maps a threshold metric and type to a key of Scenario.Metrics.
+// Numeric types are treated as percentiles, e.g. latency/95 is latency.p95.
+func MetricKey(metric, metricType string) string {
+	if metricType == "" {
+		return metric
+	}
+
+	if percentile, err := strconv.ParseFloat(metricType, 64); err == nil {
+		return metric + "." + percentileKey(percentile)
+	}
+
+	return metric + "." + metricType
+}
+
+func RulesFromThresholds(thresholds []*config.Threshold) []Rule {
+	var rules []Rule
+
+	for _, threshold := range thresholds {
+		if threshold.MaxIncrease == nil {
+			continue
+		}
+
+		rules = append(rules, Rule{
+			Metric:      MetricKey(threshold.Metric, threshold.Type),
+			MaxIncrease: *threshold.MaxIncrease,
+		})
+	}
+
+	return rules
+}
+
+// Compare diffs scenarios present in both reports and applies relative thresholds returned by rulesFor.
+// A baseline scenario missing in current report fails the comparison.
+func Compare(baseline, current *Report, rulesFor func(scenario string) []Rule) *Comparison {
+	comparison := &Comparison{Passed: true}
+
+	for _, currentScenario := range current.Scenarios {
+		baselineScenario := baseline.Scenario(currentScenario.Name)
+		if baselineScenario == nil {
+			continue
+		}
+
+		diffs := compareScenario(baselineScenario, currentScenario, rulesFor(currentScenario.Name))
+
+		for _, diff := range diffs {
+			if !diff.Passed {
+				comparison.Passed = false
+			}
+		}
+
+		comparison.Diffs = append(comparison.Diffs, diffs...)
+	}
+
+	for _, baselineScenario := range baseline.Scenarios {
+		if current.Scenario(baselineScenario.Name) != nil {
+			continue
+		}
+
+		comparison.Passed = false
+		comparison.Diffs = append(comparison.Diffs, &Diff{
+			Scenario: baselineScenario.Name,
+			Error:    "scenario is missing in current",
+		})
+	}
+
+	return comparison
+}
+
+func compareScenario(baseline, current *Scenario, rules []Rule) []*Diff {
+	baselineMetrics := baseline.Metrics()
+	currentMetrics := current.Metrics()
+
+	limits := make(map[string]config.Percent, len(rules))
+	for _, rule := range rules {
+		limits[rule.Metric] = rule.MaxIncrease
+	}
+
+	var diffs []*Diff
+
+	for _, metric := range metricsToCompare(limits) {
+		baselineValue, inBaseline := baselineMetrics[metric]
+		currentValue, inCurrent := currentMetrics[metric]
+
+		if !inBaseline || !inCurrent {
+			if limit, ok := limits[metric]; ok {
+				diffs = append(diffs, missingMetricDiff(current.Name, metric, limit, inBaseline))
+			}
+
+			continue
+		}
+
+		diff := &Diff{
+			Scenario: current.Name,
+			Metric:   metric,
+			Baseline: baselineValue,
+			Current:  currentValue,
+			Passed:   true,
+		}
+
+		change, ok := relativeChange(baselineValue, currentValue)
+		if ok {
+			diff.Change = &change
+		}
+
+		if limit, ok := limits[metric]; ok {
+			diff.MaxIncrease = &limit
+			diff.Passed = currentValue <= baselineValue || (diff.Change != nil && *diff.Change <= float64(limit))
+		}
+
+		diffs = append(diffs, diff)
+	}
+
+	return diffs
+}
+
+// missingMetricDiff fails a relative threshold of a metric which is missing in a report,
+// e.g. a percentile which is not reported or a custom metric which was not recorded.
+func missingMetricDiff(scenario, metric string, limit config.Percent, inBaseline bool) *Diff {
+	report := "baseline"
+	if inBaseline {
+		report = "current"
+	}
+
+	return &Diff{
+		Scenario:    scenario,
+		Metric:      metric,
+		MaxIncrease: &limit,
+		Error:       "metric is missing in " + report,
+	}
+}
+
+func metricsToCompare(limits map[string]config.Percent) []string {
+	metrics := append([]string{}, defaultMetrics...)
+
+	var extra []string
+
+	for metric := range limits {
+		found := false
+
+		for _, m := range metrics {
+			if m == metric {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			extra = append(extra, metric)
+		}
+	}
+
+	sort.Strings(extra)
+
+	return append(metrics, extra...)
+}
+
+func relativeChange(baseline, current float64) (float64, bool) {
+	if baseline == 0 {
+		return 0, current == 0
+	}
+
+	return (current - baseline) / baseline, true
+}
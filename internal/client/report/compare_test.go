@@ -0,0 +1,197 @@
+package report_test
+
+import (
+	"testing"
+
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/lameaux/bro/internal/client/report"
+)
+
+func makeReport(p95 int64) *report.Report {
+	return &report.Report{
+		Scenarios: []*report.Scenario{
+			{
+				Name:      "scenario",
+				Total:     100,
+				LatencyMs: map[string]int64{"p95": p95},
+			},
+		},
+	}
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	maxIncrease := config.Percent(0.1)
+
+	tests := []struct {
+		name     string
+		baseline int64
+		current  int64
+		passed   bool
+	}{
+		{
+			name:     "no change",
+			baseline: 100,
+			current:  100,
+			passed:   true,
+		},
+		{
+			name:     "within limit",
+			baseline: 100,
+			current:  110,
+			passed:   true,
+		},
+		{
+			name:     "regression",
+			baseline: 100,
+			current:  111,
+			passed:   false,
+		},
+		{
+			name:     "improvement",
+			baseline: 100,
+			current:  50,
+			passed:   true,
+		},
+		{
+			name:     "zero baseline",
+			baseline: 0,
+			current:  1,
+			passed:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			comparison := report.Compare(makeReport(tt.baseline), makeReport(tt.current), func(_ string) []report.Rule {
+				return []report.Rule{{Metric: "latency.p95", MaxIncrease: maxIncrease}}
+			})
+
+			if comparison.Passed != tt.passed {
+				t.Errorf("passed equals %v; expected %v", comparison.Passed, tt.passed)
+			}
+		})
+	}
+}
+
+func TestCompare_MissingMetric(t *testing.T) {
+	t.Parallel()
+
+	comparison := report.Compare(makeReport(100), makeReport(100), func(_ string) []report.Rule {
+		return []report.Rule{{Metric: report.MetricKey("latency", "99.9"), MaxIncrease: config.Percent(0.1)}}
+	})
+
+	if comparison.Passed {
+		t.Fatalf("expected failed comparison for unknown percentile")
+	}
+
+	diff := comparison.Diffs[len(comparison.Diffs)-1]
+	if diff.Metric != "latency.p99.9" || diff.Passed || diff.Error == "" {
+		t.Errorf("unexpected diff: %+v", diff)
+	}
+}
+
+func TestCompare_MissingScenario(t *testing.T) {
+	t.Parallel()
+
+	comparison := report.Compare(makeReport(100), &report.Report{}, func(_ string) []report.Rule {
+		return nil
+	})
+
+	if comparison.Passed {
+		t.Fatalf("expected failed comparison for missing scenario")
+	}
+
+	if len(comparison.Diffs) != 1 {
+		t.Fatalf("got %d diffs; expected 1", len(comparison.Diffs))
+	}
+
+	diff := comparison.Diffs[0]
+	if diff.Scenario != "scenario" || diff.Passed || diff.Error == "" {
+		t.Errorf("unexpected diff: %+v", diff)
+	}
+}
+
+func TestMetricKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		metric     string
+		metricType string
+		expected   string
+	}{
+		{metric: "rps", expected: "rps"},
+		{metric: "latency", metricType: "95", expected: "latency.p95"},
+		{metric: "latency", metricType: "95.0", expected: "latency.p95"},
+		{metric: "latency", metricType: "99.9", expected: "latency.p99.9"},
+		{metric: "errors", metricType: "timeout", expected: "errors.timeout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			t.Parallel()
+
+			if key := report.MetricKey(tt.metric, tt.metricType); key != tt.expected {
+				t.Errorf("key equals %v; expected %v", key, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRulesFromThresholds(t *testing.T) {
+	t.Parallel()
+
+	maxIncrease := config.Percent(0.1)
+	maxValue := 100.0
+
+	thresholds := []*config.Threshold{
+		{Metric: "latency", Type: "95", MaxIncrease: &maxIncrease},
+		{Metric: "latency", Type: "99", MaxValue: &maxValue},
+		{Metric: "rps", MaxIncrease: &maxIncrease},
+	}
+
+	rules := report.RulesFromThresholds(thresholds)
+	if len(rules) != 2 {
+		t.Fatalf("got %d rules; expected 2", len(rules))
+	}
+
+	if rules[0].Metric != "latency.p95" {
+		t.Errorf("metric equals %v; expected latency.p95", rules[0].Metric)
+	}
+
+	if rules[1].Metric != "rps" {
+		t.Errorf("metric equals %v; expected rps", rules[1].Metric)
+	}
+}
+
+func TestParsePercent(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value    string
+		expected config.Percent
+		err      bool
+	}{
+		{value: "10%", expected: 0.1},
+		{value: "0.25", expected: 0.25},
+		{value: "abc%", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+
+			percent, err := config.ParsePercent(tt.value)
+			if (err != nil) != tt.err {
+				t.Fatalf("err equals %v; expected error %v", err, tt.err)
+			}
+
+			if percent != tt.expected {
+				t.Errorf("percent equals %v; expected %v", percent, tt.expected)
+			}
+		})
+	}
+}
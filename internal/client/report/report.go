@@ -0,0 +1,331 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/lameaux/bro/internal/client/stats"
+	"github.com/lameaux/bro/internal/client/tracking"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+//nolint:gochecknoglobals
+var latencyPercentiles = []float64{50, 90, 95, 99}
+
+// Report is a machine-readable summary of a test run.
+type Report struct {
+	Name       string      `json:"name"`
+	Path       string      `json:"path,omitempty"`
+	Success    bool        `json:"success"`
+	DurationMs int64       `json:"durationMs"`
+	Scenarios  []*Scenario `json:"scenarios"`
+	// Missing are scenarios without stats, e.g. they failed to start.
+	Missing    []string    `json:"missing,omitempty"`
+	Comparison *Comparison `json:"comparison,omitempty"`
+}
+
+type ErrorSample struct {
+	Message string `json:"message"`
+	Count   int64  `json:"count"`
+}
+
+type TLS struct {
+	Handshakes int64 `json:"handshakes"`
+	Resumed    int64 `json:"resumed"`
+}
+
+// Closed contains websocket connections grouped by close code and their lifetime.
+type Closed struct {
+	Total      int64            `json:"total"`
+	Codes      map[string]int64 `json:"codes"`
+	LifetimeMs map[string]int64 `json:"lifetimeMs"`
+}
+
+// Stream contains event counts of streaming responses, their timings are in TimingsMs.
+type Stream struct {
+	Streams int64 `json:"streams"`
+	Events  int64 `json:"events"`
+}
+
+type Bytes struct {
+	Sent            int64 `json:"sent"`
+	Received        int64 `json:"received"`
+	ReceivedDecoded int64 `json:"receivedDecoded"`
+}
+
+type Throughput struct {
+	Sent     float64 `json:"sent"`
+	Received float64 `json:"received"`
+}
+
+// Breakdown contains stats of requests grouped by status code or stage.
+type Breakdown struct {
+	Name      string           `json:"name"`
+	Total     int64            `json:"total"`
+	Success   int64            `json:"success"`
+	Failed    int64            `json:"failed"`
+	Codes     map[string]int64 `json:"codes,omitempty"`
+	LatencyMs map[string]int64 `json:"latencyMs"`
+}
+
+type Scenario struct {
+	Name         string                        `json:"name"`
+	Total        int64                         `json:"total"`
+	Success      int64                         `json:"success"`
+	Failed       int64                         `json:"failed"`
+	Timeout      int64                         `json:"timeout"`
+	Invalid      int64                         `json:"invalid"`
+	Connections  int64                         `json:"connections"`
+	Reused       int64                         `json:"reused"`
+	TLS          TLS                           `json:"tls"`
+	LatencyMs    map[string]int64              `json:"latencyMs"`
+	TimingsMs    map[string]map[string]float64 `json:"timingsMs"`
+	Errors       map[string]int64              `json:"errors,omitempty"`
+	ErrorSamples []*ErrorSample                `json:"errorSamples,omitempty"`
+	Bytes        Bytes                         `json:"bytes"`
+	Throughput   Throughput                    `json:"throughputMBps"`
+	Protocols    map[string]int64              `json:"protocols,omitempty"`
+	Closed       *Closed                       `json:"closed,omitempty"`
+	Stream       *Stream                       `json:"stream,omitempty"`
+	Custom       map[string]int64              `json:"custom,omitempty"`
+	Codes        []*Breakdown                  `json:"codes,omitempty"`
+	Stages       []*Breakdown                  `json:"stages,omitempty"`
+	DurationMs   int64                         `json:"durationMs"`
+	RPS          float64                       `json:"rps"`
+	Passed       bool                          `json:"passed"`
+}
+
+func New(conf *config.Config, results *stats.Stats, success bool) *Report {
+	rep := &Report{
+		Name:       conf.Name,
+		Path:       conf.FileName,
+		Success:    success,
+		DurationMs: results.TotalDuration().Milliseconds(),
+	}
+
+	for _, scenarioName := range conf.ScenarioNames() {
+		counters := results.Counters(scenarioName)
+		if counters == nil {
+			log.Warn().
+				Dict("scenario", zerolog.Dict().Str("name", scenarioName)).
+				Msg("missing stats")
+
+			rep.Missing = append(rep.Missing, scenarioName)
+
+			continue
+		}
+
+		scenario := &Scenario{
+			Name:        scenarioName,
+			Total:       counters.Counter(stats.CounterTotal),
+			Success:     counters.Counter(stats.CounterSuccess),
+			Failed:      counters.Counter(stats.CounterFailed),
+			Timeout:     counters.Counter(stats.CounterTimeout),
+			Invalid:     counters.Counter(stats.CounterInvalid),
+			Connections: counters.Counter(stats.CounterConnections),
+			Reused:      counters.Counter(stats.CounterReused),
+			TLS: TLS{
+				Handshakes: counters.Counter(stats.CounterTLSHandshakes),
+				Resumed:    counters.Counter(stats.CounterTLSResumed),
+			},
+			LatencyMs:  make(map[string]int64, len(latencyPercentiles)),
+			TimingsMs:  make(map[string]map[string]float64, len(tracking.Phases)),
+			DurationMs: results.Duration(scenarioName).Milliseconds(),
+			RPS:        results.Rps(scenarioName),
+			Passed:     results.ThresholdsPassed(scenarioName),
+			Bytes: Bytes{
+				Sent:            counters.Counter(stats.CounterBytesSent),
+				Received:        counters.Counter(stats.CounterBytesReceived),
+				ReceivedDecoded: counters.Counter(stats.CounterBytesReceivedDecoded),
+			},
+			Throughput: Throughput{
+				Sent:     results.Throughput(scenarioName, stats.CounterBytesSent),
+				Received: results.Throughput(scenarioName, stats.CounterBytesReceived),
+			},
+		}
+
+		for _, percentile := range latencyPercentiles {
+			scenario.LatencyMs[percentileKey(percentile)] = counters.LatencyAtPercentile(percentile)
+		}
+
+		addTimings(scenario, counters, tracking.Phases)
+
+		if streams := counters.Counter(stats.CounterStreams); streams > 0 {
+			scenario.Stream = &Stream{
+				Streams: streams,
+				Events:  counters.Counter(stats.CounterEvents),
+			}
+
+			addTimings(scenario, counters, tracking.StreamPhases)
+		}
+
+		if protocols := counters.Protocols(); len(protocols) > 0 {
+			scenario.Protocols = protocols
+		}
+
+		if custom := counters.Metrics(); len(custom) > 0 {
+			scenario.Custom = custom
+		}
+
+		if closed := counters.Counter(stats.CounterClosed); closed > 0 {
+			scenario.Closed = newClosed(counters, closed)
+		}
+
+		if errorCategories := counters.ErrorCategories(); len(errorCategories) > 0 {
+			scenario.Errors = errorCategories
+		}
+
+		for _, sample := range counters.ErrorSamples() {
+			scenario.ErrorSamples = append(scenario.ErrorSamples, &ErrorSample{
+				Message: sample.Message,
+				Count:   sample.Count,
+			})
+		}
+
+		scenario.Codes = newBreakdowns(counters.CodeBreakdown(latencyPercentiles...), false)
+		scenario.Stages = newBreakdowns(counters.StageBreakdown(latencyPercentiles...), true)
+
+		rep.Scenarios = append(rep.Scenarios, scenario)
+	}
+
+	return rep
+}
+
+func addTimings(scenario *Scenario, counters *stats.Counters, phases []string) {
+	for _, phase := range phases {
+		timings := make(map[string]float64, len(latencyPercentiles))
+
+		for _, percentile := range latencyPercentiles {
+			timings[percentileKey(percentile)] = millis(counters.TimingAtPercentile(phase, percentile))
+		}
+
+		scenario.TimingsMs[phase] = timings
+	}
+}
+
+func newClosed(counters *stats.Counters, total int64) *Closed {
+	closed := &Closed{
+		Total:      total,
+		Codes:      counters.CloseCodes(),
+		LifetimeMs: make(map[string]int64, len(latencyPercentiles)),
+	}
+
+	for _, percentile := range latencyPercentiles {
+		closed.LifetimeMs[percentileKey(percentile)] = counters.LifetimeAtPercentile(percentile)
+	}
+
+	return closed
+}
+
+func newBreakdowns(items []stats.BreakdownStats, withCodes bool) []*Breakdown {
+	result := make([]*Breakdown, 0, len(items))
+
+	for _, item := range items {
+		breakdown := &Breakdown{
+			Name:      item.Name,
+			Total:     item.Total,
+			Success:   item.Success,
+			Failed:    item.Failed,
+			LatencyMs: make(map[string]int64, len(item.LatencyMillis)),
+		}
+
+		if withCodes {
+			breakdown.Codes = item.Codes
+		}
+
+		for percentile, value := range item.LatencyMillis {
+			breakdown.LatencyMs[percentileKey(percentile)] = value
+		}
+
+		result = append(result, breakdown)
+	}
+
+	return result
+}
+
+func Load(fileName string) (*Report, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var rep Report
+	if err = json.Unmarshal(data, &rep); err != nil {
+		return nil, fmt.Errorf("failed to parse file: %w", err)
+	}
+
+	return &rep, nil
+}
+
+func (r *Report) Scenario(name string) *Scenario {
+	for _, scenario := range r.Scenarios {
+		if scenario.Name == name {
+			return scenario
+		}
+	}
+
+	return nil
+}
+
+func (r *Report) Duration() time.Duration {
+	return time.Duration(r.DurationMs) * time.Millisecond
+}
+
+func (s *Scenario) Duration() time.Duration {
+	return time.Duration(s.DurationMs) * time.Millisecond
+}
+
+// Metrics flattens scenario results into named values that can be compared between runs.
+func (s *Scenario) Metrics() map[string]float64 {
+	metrics := map[string]float64{
+		MetricTotal:   float64(s.Total),
+		MetricSuccess: float64(s.Success),
+		MetricFailed:  float64(s.Failed),
+		MetricTimeout: float64(s.Timeout),
+		MetricInvalid: float64(s.Invalid),
+		MetricRPS:     s.RPS,
+
+		MetricBytes + ".sent":            float64(s.Bytes.Sent),
+		MetricBytes + ".received":        float64(s.Bytes.Received),
+		MetricBytes + ".receivedDecoded": float64(s.Bytes.ReceivedDecoded),
+		MetricThroughput + ".sent":       s.Throughput.Sent,
+		MetricThroughput + ".received":   s.Throughput.Received,
+	}
+
+	for key, value := range s.LatencyMs {
+		metrics[MetricLatency+"."+key] = float64(value)
+	}
+
+	for _, code := range s.Codes {
+		metrics[MetricCodes+"."+code.Name] = float64(code.Total)
+	}
+
+	for category, count := range s.Errors {
+		metrics[MetricErrors+"."+category] = float64(count)
+	}
+
+	for name, value := range s.Custom {
+		metrics[MetricCustom+"."+name] = float64(value)
+	}
+
+	for phase, timings := range s.TimingsMs {
+		for key, value := range timings {
+			metrics[phase+"."+key] = value
+		}
+	}
+
+	return metrics
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1e3 //nolint:mnd
+}
+
+func percentileKey(percentile float64) string {
+	return "p" + strconv.FormatFloat(percentile, 'f', -1, 64)
+}
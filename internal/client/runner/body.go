@@ -0,0 +1,208 @@
+package runner
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"github.com/lameaux/bro/internal/client/tracking"
+)
+
+var errUnknownEncoding = errors.New("unknown encoding")
+
+const (
+	encodingGzip   = "gzip"
+	encodingBrotli = "br"
+	encodingZstd   = "zstd"
+)
+
+//nolint:gochecknoglobals
+var decoders = map[string]func(src io.Reader) (io.Reader, error){
+	encodingGzip: func(src io.Reader) (io.Reader, error) {
+		return gzip.NewReader(src) //nolint:wrapcheck
+	},
+	encodingBrotli: func(src io.Reader) (io.Reader, error) {
+		return brotli.NewReader(src), nil
+	},
+	encodingZstd: func(src io.Reader) (io.Reader, error) {
+		zr, err := zstd.NewReader(src, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+
+		return zr.IOReadCloser(), nil
+	},
+}
+
+type countingReader struct {
+	io.Reader
+
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.n += int64(n)
+
+	return n, err //nolint:wrapcheck
+}
+
+// decodingReader creates decoder on first read, so errors are reported as body read errors.
+type decodingReader struct {
+	src      io.Reader
+	encoding string
+	decoder  io.Reader
+}
+
+func (r *decodingReader) Read(p []byte) (int, error) {
+	if r.decoder == nil {
+		decoder, err := decoders[r.encoding](r.src)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create %s reader: %w", r.encoding, err)
+		}
+
+		r.decoder = decoder
+	}
+
+	return r.decoder.Read(p) //nolint:wrapcheck
+}
+
+func (r *decodingReader) Close() error {
+	if closer, ok := r.decoder.(io.Closer); ok {
+		return closer.Close() //nolint:wrapcheck
+	}
+
+	return nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// bodyCloser releases decoder together with response body.
+type bodyCloser struct {
+	body    io.Closer
+	decoder *decodingReader
+}
+
+func (c *bodyCloser) Close() error {
+	if c.decoder != nil {
+		_ = c.decoder.Close()
+	}
+
+	return c.body.Close() //nolint:wrapcheck
+}
+
+// responseBody wraps response body to count bytes on the wire and after decompression.
+type responseBody struct {
+	*timedBody
+
+	wire    *countingReader
+	decoded *countingReader
+}
+
+func newResponseBody(resp *http.Response) *responseBody {
+	wire := &countingReader{Reader: resp.Body}
+
+	var (
+		reader  io.Reader = wire
+		decoder *decodingReader
+	)
+
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	if _, ok := decoders[encoding]; ok {
+		decoder = &decodingReader{src: wire, encoding: encoding}
+		reader = decoder
+
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = -1
+		resp.Uncompressed = true
+	}
+
+	decoded := &countingReader{Reader: reader}
+
+	body := &responseBody{
+		timedBody: &timedBody{ReadCloser: readCloser{
+			Reader: decoded,
+			Closer: &bodyCloser{body: resp.Body, decoder: decoder},
+		}},
+		wire:    wire,
+		decoded: decoded,
+	}
+
+	resp.Body = body
+
+	return body
+}
+
+// bufferResponseBody reads response body and replaces it with a copy, so it can be read again.
+func bufferResponseBody(resp *http.Response) ([]byte, error) {
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", tracking.ErrBodyRead, err)
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+
+	return data, nil
+}
+
+// compressBody encodes request body with gzip, br or zstd.
+func compressBody(encoding string, data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	var writer io.WriteCloser
+
+	switch encoding {
+	case encodingGzip:
+		writer = gzip.NewWriter(&buf)
+	case encodingBrotli:
+		writer = brotli.NewWriter(&buf)
+	case encodingZstd:
+		zw, err := zstd.NewWriter(&buf, zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd writer: %w", err)
+		}
+
+		writer = zw
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnknownEncoding, encoding)
+	}
+
+	if _, err := writer.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to compress body: %w", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress body: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func headerSize(header http.Header) int64 {
+	var size int64
+
+	for key, values := range header {
+		for _, value := range values {
+			size += int64(len(key) + len(": ") + len(value) + len("\r\n"))
+		}
+	}
+
+	return size
+}
+
+// responseHeaderSize estimates size of status line and headers.
+func responseHeaderSize(resp *http.Response) int64 {
+	statusLine := len(resp.Proto) + len(" ") + len(resp.Status) + len("\r\n")
+
+	return int64(statusLine) + headerSize(resp.Header) + int64(len("\r\n"))
+}
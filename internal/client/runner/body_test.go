@@ -0,0 +1,106 @@
+package runner
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestNewResponseBody(t *testing.T) {
+	t.Parallel()
+
+	content := strings.Repeat("hello", 100)
+
+	var compressed bytes.Buffer
+
+	zw := gzip.NewWriter(&compressed)
+	_, _ = zw.Write([]byte(content))
+	_ = zw.Close()
+
+	compressedSize := int64(compressed.Len())
+
+	resp := &http.Response{
+		Header: http.Header{"Content-Encoding": []string{"gzip"}},
+		Body:   io.NopCloser(&compressed),
+	}
+
+	body := newResponseBody(resp)
+
+	decoded, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read body: %v", err)
+	}
+
+	if string(decoded) != content {
+		t.Errorf("body was not decompressed")
+	}
+
+	if _, err = body.drain(); err != nil {
+		t.Errorf("drain returned error: %v", err)
+	}
+
+	if body.wire.n != compressedSize {
+		t.Errorf("wire bytes equals %d; expected %d", body.wire.n, compressedSize)
+	}
+
+	if body.decoded.n != int64(len(content)) {
+		t.Errorf("decoded bytes equals %d; expected %d", body.decoded.n, len(content))
+	}
+
+	if resp.Header.Get("Content-Encoding") != "" {
+		t.Errorf("content encoding header was not removed")
+	}
+}
+
+func TestNewResponseBodyEncodings(t *testing.T) {
+	t.Parallel()
+
+	content := strings.Repeat("hello", 100)
+
+	for _, encoding := range []string{encodingGzip, encodingBrotli, encodingZstd} {
+		t.Run(encoding, func(t *testing.T) {
+			t.Parallel()
+
+			compressed, err := compressBody(encoding, []byte(content))
+			if err != nil {
+				t.Fatalf("failed to compress body: %v", err)
+			}
+
+			resp := &http.Response{
+				Header: http.Header{"Content-Encoding": []string{encoding}},
+				Body:   io.NopCloser(bytes.NewReader(compressed)),
+			}
+
+			body := newResponseBody(resp)
+
+			decoded, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("failed to read body: %v", err)
+			}
+
+			if string(decoded) != content {
+				t.Errorf("body was not decompressed")
+			}
+
+			if err = resp.Body.Close(); err != nil {
+				t.Errorf("close returned error: %v", err)
+			}
+
+			if body.wire.n != int64(len(compressed)) {
+				t.Errorf("wire bytes equals %d; expected %d", body.wire.n, len(compressed))
+			}
+		})
+	}
+}
+
+func TestCompressBodyUnknownEncoding(t *testing.T) {
+	t.Parallel()
+
+	if _, err := compressBody("deflate", []byte("hello")); !errors.Is(err, errUnknownEncoding) {
+		t.Errorf("err equals %v; expected %v", err, errUnknownEncoding)
+	}
+}
@@ -1,11 +1,18 @@
 package runner
 
 import (
+	"net/http"
 	"time"
 
 	"github.com/lameaux/bro/internal/client/tracking"
 )
 
+// HTTPClients provides an http client for each sender thread.
+type HTTPClients interface {
+	Client(threadID int) *http.Client
+	RequestDone(threadID int)
+}
+
 type StatListener interface {
 	TrackFailed(
 		info *tracking.RequestInfo,
@@ -17,3 +24,18 @@ type StatListener interface {
 		latency time.Duration,
 	)
 }
+
+// StageListener is an optional extension of StatListener notified when a stage starts.
+type StageListener interface {
+	TrackStage(info *tracking.StageInfo)
+}
+
+// RequestListener is an optional extension of StatListener notified before a request is sent.
+type RequestListener interface {
+	TrackRequest(info *tracking.RequestInfo)
+}
+
+// ConnectionListener is an optional extension of StatListener notified when a websocket connection is closed.
+type ConnectionListener interface {
+	TrackConnection(info *tracking.ConnectionInfo)
+}
@@ -0,0 +1,133 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/lameaux/bro/internal/client/checker"
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/lameaux/bro/internal/client/extractor"
+	"github.com/lameaux/bro/internal/client/tracking"
+	"github.com/miekg/dns"
+)
+
+const (
+	protocolDNS    = "dns"
+	defaultDNSPort = "53"
+)
+
+var (
+	errMissingDNSServer    = errors.New("server and name must be set")
+	errUnknownDNSType      = errors.New("unknown record type")
+	errUnknownDNSTransport = errors.New("unknown transport")
+)
+
+// dnsExecutor sends queries of a scenario, a new socket is used for every query.
+// Truncated udp responses are retried over tcp like resolvers do.
+type dnsExecutor struct {
+	conf      *config.DNSRequest
+	client    *dns.Client
+	tcpClient *dns.Client
+	server    string
+	qtype     uint16
+}
+
+func newDNSExecutor(_ HTTPClients) Executor { //nolint:ireturn
+	return &dnsExecutor{}
+}
+
+func (e *dnsExecutor) Prepare(_ context.Context, scenario *config.Scenario) (Labels, error) {
+	conf := scenario.DNSRequest
+	if conf == nil || conf.Server == "" || conf.Name == "" {
+		return Labels{}, errMissingDNSServer
+	}
+
+	qtype, ok := dns.StringToType[strings.ToUpper(conf.Type())]
+	if !ok {
+		return Labels{}, fmt.Errorf("%w: %s", errUnknownDNSType, conf.Type())
+	}
+
+	switch conf.Transport() {
+	case config.DNSTransportUDP, config.DNSTransportTCP:
+	default:
+		return Labels{}, fmt.Errorf("%w: %s", errUnknownDNSTransport, conf.Transport())
+	}
+
+	e.server = conf.Server
+	if _, _, err := net.SplitHostPort(e.server); err != nil {
+		e.server = net.JoinHostPort(e.server, defaultDNSPort)
+	}
+
+	e.conf = conf
+	e.qtype = qtype
+	e.client = &dns.Client{
+		Net:     conf.Transport(),
+		Timeout: conf.Timeout(),
+	}
+	e.tcpClient = &dns.Client{
+		Net:     config.DNSTransportTCP,
+		Timeout: conf.Timeout(),
+	}
+
+	return Labels{
+		Method:   conf.Type(),
+		URL:      conf.Name,
+		Protocol: protocolDNS + "/" + conf.Transport(),
+	}, nil
+}
+
+func (e *dnsExecutor) Execute(ctx context.Context, req *Request) (*Response, error) {
+	msg := e.message(req.Vars)
+
+	startTime := time.Now()
+
+	response, _, err := e.client.ExchangeContext(ctx, msg, e.server)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send dns query: %w", err)
+	}
+
+	result := &Response{
+		Code: dns.RcodeToString[response.Rcode],
+		Bytes: tracking.Bytes{
+			SentBody:            int64(msg.Len()),
+			ReceivedBody:        int64(response.Len()),
+			ReceivedBodyDecoded: int64(response.Len()),
+		},
+	}
+
+	if response.Truncated && e.client.Net == config.DNSTransportUDP {
+		response, _, err = e.tcpClient.ExchangeContext(ctx, msg, e.server)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retry truncated dns query over tcp: %w", err)
+		}
+
+		result.Code = dns.RcodeToString[response.Rcode]
+		result.Protocol = protocolDNS + "/" + config.DNSTransportTCP
+		result.Bytes.SentBody += int64(msg.Len())
+		result.Bytes.ReceivedBody += int64(response.Len())
+		result.Bytes.ReceivedBodyDecoded += int64(response.Len())
+	}
+
+	result.Latency = time.Since(startTime)
+	result.Value = response
+
+	return result, nil
+}
+
+func (e *dnsExecutor) Check(checks []*config.Check, resp *Response) ([]checker.Result, bool) {
+	response, _ := resp.Value.(*dns.Msg)
+
+	return checker.New(checks).ValidateDNS(response)
+}
+
+func (e *dnsExecutor) message(vars map[string]string) *dns.Msg {
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(extractor.Expand(e.conf.Name, vars)), e.qtype)
+	msg.RecursionDesired = !e.conf.NoRecursion
+
+	return msg
+}
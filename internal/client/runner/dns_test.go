@@ -0,0 +1,203 @@
+package runner
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/lameaux/bro/internal/client/checker"
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/lameaux/bro/internal/client/thresholds"
+	"github.com/lameaux/bro/internal/client/tracking"
+	"github.com/miekg/dns"
+)
+
+// startDNSStub answers A queries for bro.test. over udp and tcp on the same port, other names do not exist.
+// Answers for truncated.bro.test. are truncated over udp.
+func startDNSStub(t *testing.T) string {
+	t.Helper()
+
+	handler := dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		msg := new(dns.Msg)
+		msg.SetReply(req)
+
+		question := req.Question[0]
+
+		switch {
+		case question.Name != "bro.test." && question.Name != "truncated.bro.test.":
+			msg.Rcode = dns.RcodeNameError
+		case question.Name == "truncated.bro.test." && w.RemoteAddr().Network() == "udp":
+			msg.Truncated = true
+		case question.Qtype == dns.TypeA:
+			msg.Answer = append(msg.Answer, &dns.A{
+				Hdr: dns.RR_Header{Name: question.Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 60},
+				A:   net.ParseIP("10.0.0.7"),
+			})
+		}
+
+		_ = w.WriteMsg(msg)
+	})
+
+	packetConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen udp: %v", err)
+	}
+
+	listener, err := net.Listen("tcp", packetConn.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("failed to listen tcp: %v", err)
+	}
+
+	for _, server := range []*dns.Server{
+		{PacketConn: packetConn, Handler: handler},
+		{Listener: listener, Handler: handler},
+	} {
+		started := make(chan struct{})
+		server.NotifyStartedFunc = func() { close(started) }
+
+		go func() { _ = server.ActivateAndServe() }()
+
+		<-started
+
+		t.Cleanup(func() { _ = server.Shutdown() })
+	}
+
+	return packetConn.LocalAddr().String()
+}
+
+func newDNSRunner(t *testing.T, conf *config.DNSRequest, checks []*config.Check) (*Runner, *recordingListener) {
+	t.Helper()
+
+	listener := &recordingListener{}
+
+	r := New(nil, 0, &config.Scenario{Name: "dns " + conf.Name, DNSRequest: conf, Checks: checks}, thresholds.NewCheckCounters(), []StatListener{listener})
+	if err := r.prepare(context.Background()); err != nil {
+		t.Fatalf("failed to prepare: %v", err)
+	}
+
+	return r, listener
+}
+
+func TestProcessDNSMessage(t *testing.T) {
+	server := startDNSStub(t)
+
+	for _, transport := range []string{config.DNSTransportUDP, config.DNSTransportTCP} {
+		r, listener := newDNSRunner(t, &config.DNSRequest{
+			Server:       server,
+			Name:         "bro.test",
+			TransportRaw: transport,
+		}, []*config.Check{
+			{Type: checker.TypeDNSRcode, Equals: "NOERROR"},
+			{Type: checker.TypeDNSAnswer, Name: "A", Equals: "10.0.0.7"},
+		})
+
+		r.processMessage(context.Background(), 0, 1)
+
+		if len(listener.responses) != 1 || listener.successes != 1 {
+			t.Fatalf("%s: expected successful response, got %d failed", transport, len(listener.failed))
+		}
+
+		info := listener.responses[0]
+		if info.Code != "NOERROR" || info.Method != "A" || info.Protocol != "dns/"+transport {
+			t.Errorf("%s: unexpected request info: %+v", transport, info)
+		}
+
+		if info.Bytes.Received() == 0 {
+			t.Errorf("%s: received bytes are not counted", transport)
+		}
+	}
+}
+
+func TestProcessDNSMessage_Truncated(t *testing.T) {
+	server := startDNSStub(t)
+
+	r, listener := newDNSRunner(t, &config.DNSRequest{
+		Server: server,
+		Name:   "truncated.bro.test",
+	}, []*config.Check{
+		{Type: checker.TypeDNSRcode},
+		{Type: checker.TypeDNSAnswer, Name: "A", Equals: "10.0.0.7"},
+	})
+
+	r.processMessage(context.Background(), 0, 1)
+
+	if len(listener.responses) != 1 || listener.successes != 1 {
+		t.Fatalf("expected successful response retried over tcp, got %d failed", len(listener.failed))
+	}
+
+	if protocol := listener.responses[0].Protocol; protocol != "dns/tcp" {
+		t.Errorf("protocol equals %s; expected dns/tcp", protocol)
+	}
+}
+
+func TestProcessDNSMessage_NXDomain(t *testing.T) {
+	server := startDNSStub(t)
+
+	r, listener := newDNSRunner(t, &config.DNSRequest{
+		Server:  server,
+		Name:    "missing.test",
+		TypeRaw: "aaaa",
+	}, []*config.Check{
+		{Type: checker.TypeDNSRcode, Equals: "NOERROR"},
+	})
+
+	r.processMessage(context.Background(), 0, 1)
+
+	if len(listener.responses) != 1 || listener.successes != 0 || listener.responses[0].Code != "NXDOMAIN" {
+		t.Fatalf("expected invalid NXDOMAIN response")
+	}
+}
+
+func TestProcessDNSMessage_Timeout(t *testing.T) {
+	// nothing answers on this socket
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	r, listener := newDNSRunner(t, &config.DNSRequest{
+		Server:     conn.LocalAddr().String(),
+		Name:       "bro.test",
+		TimeoutRaw: 100 * time.Millisecond,
+	}, nil)
+
+	r.processMessage(context.Background(), 0, 1)
+
+	if len(listener.failed) != 1 || listener.failed[0].ErrorCategory != tracking.ErrorTimeout {
+		t.Fatalf("expected timeout, got %d responses", len(listener.responses))
+	}
+}
+
+func TestDNSExecutor_Prepare(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		conf *config.DNSRequest
+		err  bool
+	}{
+		{name: "missing name", conf: &config.DNSRequest{Server: "127.0.0.1"}, err: true},
+		{name: "unknown type", conf: &config.DNSRequest{Server: "127.0.0.1", Name: "a", TypeRaw: "XYZ"}, err: true},
+		{name: "unknown transport", conf: &config.DNSRequest{Server: "127.0.0.1", Name: "a", TransportRaw: "quic"}, err: true},
+		{name: "srv", conf: &config.DNSRequest{Server: "127.0.0.1", Name: "_http._tcp.a", TypeRaw: "SRV"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			executor := &dnsExecutor{}
+
+			_, err := executor.Prepare(context.Background(), &config.Scenario{DNSRequest: tt.conf})
+			if (err != nil) != tt.err {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if err == nil && executor.server != "127.0.0.1:53" {
+				t.Errorf("server equals %s; expected default port", executor.server)
+			}
+		})
+	}
+}
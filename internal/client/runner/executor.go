@@ -0,0 +1,123 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lameaux/bro/internal/client/checker"
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/lameaux/bro/internal/client/tracking"
+)
+
+var (
+	errUnknownScenarioType = errors.New("unknown scenario type")
+	errMissingRequest      = errors.New("missing request")
+	errInvalidExecutor     = errors.New("executor must implement RequestExecutor or SessionExecutor")
+)
+
+// Executor sends requests of one protocol, it is a RequestExecutor or a SessionExecutor.
+// Runner drives it with rate generator and sender threads, tracks its responses with stat listeners
+// and counts check results for thresholds.
+// Executors which implement io.Closer are closed when scenario ends.
+type Executor interface {
+	// Prepare is called once before scenario starts, e.g. to validate settings or load a payload.
+	// It returns labels of requests in stats and brod.
+	Prepare(ctx context.Context, scenario *config.Scenario) (Labels, error)
+}
+
+// RequestExecutor sends a request and receives a single response, e.g. http or dns.
+type RequestExecutor interface {
+	Executor
+	// Execute sends a request and reads its response. Failed requests return an error,
+	// which is tracked with an error category, and may return a response to report its code.
+	Execute(ctx context.Context, req *Request) (*Response, error)
+	// Check validates a response with scenario checks, results are in the same order as checks.
+	Check(checks []*config.Check, resp *Response) ([]checker.Result, bool)
+}
+
+// SessionExecutor runs a session which reports several responses with a tracker,
+// e.g. a websocket connection and its messages.
+type SessionExecutor interface {
+	Executor
+	// Session is run for every request, or repeated until ctx is done for held sessions, see Request.Held.
+	Session(ctx context.Context, req *Request, tracker *Tracker)
+	// Held is a number of sessions kept open for scenario duration, they are opened at rps rate
+	// and reopened when they end. Zero runs a session for every request.
+	Held() int
+}
+
+// ExecutorFactory creates an executor for a scenario, http based executors use http clients of the runner.
+type ExecutorFactory func(httpClients HTTPClients) Executor
+
+// Labels are the same for all requests of a scenario.
+type Labels struct {
+	Method string
+	URL    string
+	// Protocol is a default, it can be overridden by a response, e.g. HTTP/2.0.
+	Protocol string
+}
+
+// Request is sent by a sender thread, Vars are variables of the thread, which can be updated by extractors.
+type Request struct {
+	ThreadID int
+	MsgID    int
+	Vars     map[string]string
+	// Held is set for sessions kept open until scenario ends, see SessionExecutor.Held.
+	Held bool
+}
+
+// Response is a result of a request, Value is a protocol specific response passed to Check.
+type Response struct {
+	// Method overrides the label of scenario, e.g. MESSAGE for websocket messages.
+	Method   string
+	Code     string
+	Protocol string
+	Latency  time.Duration
+	Timings  tracking.Timings
+	Bytes    tracking.Bytes
+	Stream   *tracking.StreamInfo
+	// Metrics are custom counters, e.g. reported by a scenario script.
+	Metrics map[string]int64
+	// Checks are checks which are not scenario checks, e.g. stream checks,
+	// their CheckResults are counted for thresholds when response passes to Check.
+	Checks       []*config.Check
+	CheckResults []checker.Result
+	Value        any
+}
+
+//nolint:gochecknoglobals
+var (
+	executorsMu sync.RWMutex
+	executors   = map[string]ExecutorFactory{
+		config.ScenarioTypeHTTP:      newHTTPExecutor,
+		config.ScenarioTypeGraphQL:   newHTTPExecutor,
+		config.ScenarioTypeGRPC:      newGRPCExecutor,
+		config.ScenarioTypeTCP:       newSocketExecutor(networkTCP),
+		config.ScenarioTypeUDP:       newSocketExecutor(networkUDP),
+		config.ScenarioTypeDNS:       newDNSExecutor,
+		config.ScenarioTypeWebSocket: newWebSocketExecutor,
+	}
+)
+
+// RegisterExecutor adds an executor for scenarios with the type, it replaces a registered one.
+func RegisterExecutor(scenarioType string, factory ExecutorFactory) {
+	executorsMu.Lock()
+	defer executorsMu.Unlock()
+
+	executors[scenarioType] = factory
+}
+
+func newExecutor(scenarioType string, httpClients HTTPClients) (Executor, error) { //nolint:ireturn
+	executorsMu.RLock()
+	defer executorsMu.RUnlock()
+
+	factory, ok := executors[scenarioType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errUnknownScenarioType, scenarioType)
+	}
+
+	return factory(httpClients), nil
+}
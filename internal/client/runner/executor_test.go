@@ -0,0 +1,118 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lameaux/bro/internal/client/checker"
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/lameaux/bro/internal/client/thresholds"
+)
+
+var errFakeFailed = errors.New("fake failed")
+
+// fakeExecutor replies with the key param, and fails every second request.
+type fakeExecutor struct {
+	key string
+}
+
+func (e *fakeExecutor) Prepare(_ context.Context, scenario *config.Scenario) (Labels, error) {
+	key, ok := scenario.Params["key"].(string)
+	if !ok {
+		return Labels{}, errMissingRequest
+	}
+
+	e.key = key
+
+	return Labels{Method: "GET", URL: key, Protocol: "fake"}, nil
+}
+
+func (e *fakeExecutor) Execute(_ context.Context, req *Request) (*Response, error) {
+	if req.MsgID%2 == 0 {
+		return &Response{Code: "failed"}, errFakeFailed
+	}
+
+	return &Response{Code: "ok", Value: e.key + req.Vars["suffix"]}, nil
+}
+
+func (e *fakeExecutor) Check(checks []*config.Check, resp *Response) ([]checker.Result, bool) {
+	results := make([]checker.Result, 0, len(checks))
+	success := true
+
+	for _, check := range checks {
+		var err error
+		if resp.Value != check.Equals {
+			err = fmt.Errorf("%w: %v", errFakeFailed, resp.Value)
+			success = false
+		}
+
+		results = append(results, checker.Result{Actual: fmt.Sprint(resp.Value), Pass: err == nil, Error: err})
+	}
+
+	return results, success
+}
+
+func TestRegisterExecutor(t *testing.T) {
+	executor := &fakeExecutor{}
+
+	RegisterExecutor("fake", func(_ HTTPClients) Executor { return executor })
+
+	listener := &recordingListener{}
+
+	r := New(nil, 0, &config.Scenario{
+		Name:    "fake",
+		TypeRaw: "fake",
+		Params:  map[string]any{"key": "value"},
+		Checks:  []*config.Check{{Type: "fake", Equals: "value-1"}},
+	}, thresholds.NewCheckCounters(), []StatListener{listener})
+	if err := r.prepare(context.Background()); err != nil {
+		t.Fatalf("failed to prepare: %v", err)
+	}
+
+	r.threadVars(0)["suffix"] = "-1"
+
+	r.processMessage(context.Background(), 0, 1)
+	r.processMessage(context.Background(), 0, 2)
+
+	if len(listener.responses) != 1 || listener.successes != 1 {
+		t.Fatalf("expected successful response, got %d responses", len(listener.responses))
+	}
+
+	info := listener.responses[0]
+	if info.Code != "ok" || info.URL != "value" || info.Protocol != "fake" {
+		t.Errorf("unexpected request info: %+v", info)
+	}
+
+	if len(listener.failed) != 1 || listener.failed[0].Code != "failed" {
+		t.Errorf("expected failed request with code, got %+v", listener.failed)
+	}
+}
+
+func TestNewExecutor(t *testing.T) {
+	t.Parallel()
+
+	if _, err := newExecutor("unknown", nil); !errors.Is(err, errUnknownScenarioType) {
+		t.Errorf("expected unknown scenario type, got %v", err)
+	}
+
+	r := New(nil, 0, &config.Scenario{TypeRaw: config.ScenarioTypeGRPC}, thresholds.NewCheckCounters(), nil)
+	if err := r.prepare(context.Background()); !errors.Is(err, errMissingRequest) {
+		t.Errorf("expected missing request, got %v", err)
+	}
+
+	RegisterExecutor("prepare only", func(_ HTTPClients) Executor { return &prepareOnlyExecutor{} })
+
+	r = New(nil, 0, &config.Scenario{TypeRaw: "prepare only"}, thresholds.NewCheckCounters(), nil)
+	if err := r.prepare(context.Background()); !errors.Is(err, errInvalidExecutor) {
+		t.Errorf("expected invalid executor, got %v", err)
+	}
+}
+
+// prepareOnlyExecutor implements neither RequestExecutor nor SessionExecutor.
+type prepareOnlyExecutor struct{}
+
+func (e *prepareOnlyExecutor) Prepare(_ context.Context, _ *config.Scenario) (Labels, error) {
+	return Labels{}, nil
+}
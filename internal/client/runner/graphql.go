@@ -0,0 +1,112 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/lameaux/bro/internal/client/tracking"
+)
+
+const contentTypeJSON = "application/json"
+
+var (
+	errMissingQuery   = errors.New("one of query and queryFile must be set")
+	errMultipleQuery  = errors.New("only one of query and queryFile can be set")
+	operationNameExpr = regexp.MustCompile(`(?m)^\s*(?:query|mutation|subscription)\s+([_A-Za-z][_0-9A-Za-z]*)`)
+)
+
+type graphQLBody struct {
+	Query         string         `json:"query"`
+	Variables     map[string]any `json:"variables,omitempty"`
+	OperationName string         `json:"operationName,omitempty"`
+}
+
+// graphQLRequest converts an operation to a POST request with JSON body and returns operation name.
+// Body is an inline template, so ${var} can be used in query and variables, values are JSON escaped.
+func graphQLRequest(conf *config.GraphQLRequest, request config.HTTPRequest) (config.HTTPRequest, string, error) {
+	query := conf.Query
+
+	switch {
+	case query != "" && conf.QueryFile != "":
+		return request, "", errMultipleQuery
+	case conf.QueryFile != "":
+		data, err := os.ReadFile(conf.QueryFile)
+		if err != nil {
+			return request, "", fmt.Errorf("failed to read query file: %w", err)
+		}
+
+		query = string(data)
+	case query == "":
+		return request, "", errMissingQuery
+	}
+
+	body, err := json.Marshal(graphQLBody{
+		Query:         query,
+		Variables:     conf.Variables,
+		OperationName: conf.OperationName,
+	})
+	if err != nil {
+		return request, "", fmt.Errorf("failed to encode graphql request: %w", err)
+	}
+
+	bodyRaw := string(body)
+
+	request.URL = conf.URL
+	request.MethodRaw = http.MethodPost
+	request.BodyRaw = &bodyRaw
+	request.BodyFile = ""
+	request.BodyBase64 = ""
+	request.Form = nil
+	request.Multipart = nil
+
+	return request, operationName(conf.OperationName, query), nil
+}
+
+// operationName returns name of the first operation if it is not set explicitly, anonymous operations have no name.
+func operationName(name, query string) string {
+	if name != "" {
+		return name
+	}
+
+	if match := operationNameExpr.FindStringSubmatch(query); match != nil {
+		return match[1]
+	}
+
+	return ""
+}
+
+type graphQLResponse struct {
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// readGraphQLResponse reads response body and returns an error if it contains errors array.
+// Body is replaced with a copy, so it can be validated by checks.
+func readGraphQLResponse(resp *http.Response) error {
+	data, err := bufferResponseBody(resp)
+	if err != nil {
+		return err
+	}
+
+	var response graphQLResponse
+	if err = json.Unmarshal(data, &response); err != nil {
+		// not a graphql response, e.g. an error page, left to checks
+		return nil
+	}
+
+	if len(response.Errors) == 0 {
+		return nil
+	}
+
+	if len(response.Errors) > 1 {
+		return fmt.Errorf("%w: %s (and %d more)", tracking.ErrGraphQL, response.Errors[0].Message, len(response.Errors)-1)
+	}
+
+	return fmt.Errorf("%w: %s", tracking.ErrGraphQL, response.Errors[0].Message)
+}
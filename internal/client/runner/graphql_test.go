@@ -0,0 +1,234 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lameaux/bro/internal/client/checker"
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/lameaux/bro/internal/client/thresholds"
+	"github.com/lameaux/bro/internal/client/tracking"
+	"github.com/lameaux/bro/internal/shared/httpclient"
+)
+
+func TestOperationName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		operation string
+		query     string
+		expected  string
+	}{
+		{
+			name:      "explicit name",
+			operation: "GetUser",
+			query:     "query GetUser { user { id } } query GetOrder { order { id } }",
+			expected:  "GetUser",
+		},
+		{
+			name:     "first operation",
+			query:    "# users\nquery GetUser($id: ID!) { user(id: $id) { id } }",
+			expected: "GetUser",
+		},
+		{
+			name:     "mutation",
+			query:    "mutation CreateUser { createUser { id } }",
+			expected: "CreateUser",
+		},
+		{
+			name:     "anonymous",
+			query:    "{ user { id } }",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if name := operationName(tt.operation, tt.query); name != tt.expected {
+				t.Errorf("operationName returned %q; expected %q", name, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGraphQLRequest(t *testing.T) {
+	t.Parallel()
+
+	if _, _, err := graphQLRequest(&config.GraphQLRequest{URL: "http://localhost"}, config.HTTPRequest{}); err == nil {
+		t.Errorf("expected error without query")
+	}
+
+	request, operation, err := graphQLRequest(&config.GraphQLRequest{
+		URL:       "http://localhost/graphql",
+		Query:     "query GetUser($id: ID!) { user(id: $id) { id } }",
+		Variables: map[string]any{"id": "${userId}"},
+	}, config.HTTPRequest{URL: "http://localhost/other", AcceptEncoding: "br"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if operation != "GetUser" {
+		t.Errorf("operation equals %q; expected GetUser", operation)
+	}
+
+	if request.URL != "http://localhost/graphql" || request.Method() != http.MethodPost || request.AcceptEncoding != "br" {
+		t.Errorf("unexpected request: %+v", request)
+	}
+
+	var body graphQLBody
+	if err = json.Unmarshal([]byte(*request.BodyRaw), &body); err != nil {
+		t.Fatalf("invalid body: %v", err)
+	}
+
+	if body.Variables["id"] != "${userId}" {
+		t.Errorf("unexpected variables: %v", body.Variables)
+	}
+}
+
+func TestGraphQLRequest_QuotedVariable(t *testing.T) {
+	t.Parallel()
+
+	executor := &httpExecutor{}
+
+	_, err := executor.Prepare(context.Background(), &config.Scenario{
+		GraphQLRequest: &config.GraphQLRequest{
+			URL:       "http://localhost/graphql",
+			Query:     `query Search($name: String!) { users(name: $name, note: "${name}") { id } }`,
+			Variables: map[string]any{"name": "${name}"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to prepare: %v", err)
+	}
+
+	name := `O"Brien \ <new>` + "\n"
+
+	var body graphQLBody
+	if err = json.Unmarshal([]byte(*executor.body.text(map[string]string{"name": name})), &body); err != nil {
+		t.Fatalf("invalid body: %v", err)
+	}
+
+	if body.Variables["name"] != name || !strings.Contains(body.Query, `note: "`+name+`"`) {
+		t.Errorf("unexpected body: %+v", body)
+	}
+}
+
+func TestReadGraphQLResponse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		body string
+		err  bool
+	}{
+		{
+			name: "data",
+			body: `{"data": {"user": {"id": "1"}}}`,
+		},
+		{
+			name: "errors",
+			body: `{"data": null, "errors": [{"message": "not found"}, {"message": "denied"}]}`,
+			err:  true,
+		},
+		{
+			name: "not json",
+			body: `<html>Bad Gateway</html>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			resp := &http.Response{Body: io.NopCloser(strings.NewReader(tt.body))}
+
+			err := readGraphQLResponse(resp)
+			if (err != nil) != tt.err || (err != nil && !errors.Is(err, tracking.ErrGraphQL)) {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			// body is left for checks
+			if body, _ := io.ReadAll(resp.Body); string(body) != tt.body {
+				t.Errorf("body was not restored: %q", body)
+			}
+		})
+	}
+}
+
+func TestProcessMessage_GraphQL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body graphQLBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || r.Header.Get("Content-Type") != contentTypeJSON {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		if body.Variables["id"] == "0" {
+			_, _ = io.WriteString(w, `{"data": {"user": null}, "errors": [{"message": "user not found"}]}`)
+
+			return
+		}
+
+		_, _ = io.WriteString(w, `{"data": {"user": {"id": "1"}}}`)
+	}))
+	t.Cleanup(server.Close)
+
+	clients, err := httpclient.NewClients(config.HTTPClient{})
+	if err != nil {
+		t.Fatalf("failed to create clients: %v", err)
+	}
+
+	for _, id := range []string{"1", "0"} {
+		scenario := &config.Scenario{
+			Name: "graphql" + id,
+			GraphQLRequest: &config.GraphQLRequest{
+				URL:       server.URL,
+				Query:     "query GetUser($id: ID!) { user(id: $id) { id } }",
+				Variables: map[string]any{"id": id},
+			},
+			Checks: []*config.Check{
+				{Type: checker.TypeHTTPBody, Contains: "user"},
+			},
+		}
+
+		listener := &recordingListener{}
+
+		r := New(clients, 0, scenario, thresholds.NewCheckCounters(), []StatListener{listener})
+		if err = r.prepare(context.Background()); err != nil {
+			t.Fatalf("failed to prepare: %v", err)
+		}
+
+		r.processMessage(context.Background(), 0, 1)
+
+		var info *tracking.RequestInfo
+
+		switch id {
+		case "1":
+			if len(listener.responses) != 1 || listener.successes != 1 {
+				t.Fatalf("expected successful response, got %d failed", len(listener.failed))
+			}
+
+			info = listener.responses[0]
+		default:
+			if len(listener.failed) != 1 || listener.failed[0].ErrorCategory != tracking.ErrorGraphQL {
+				t.Fatalf("expected graphql error, got %d responses", len(listener.responses))
+			}
+
+			info = listener.failed[0]
+		}
+
+		if info.URL != "GetUser" || info.Code != "200" {
+			t.Errorf("unexpected request info: %+v", info)
+		}
+	}
+}
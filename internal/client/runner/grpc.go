@@ -0,0 +1,93 @@
+package runner
+
+import (
+	"context"
+	"time"
+
+	"github.com/lameaux/bro/internal/client/checker"
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/lameaux/bro/internal/client/grpcclient"
+	"github.com/lameaux/bro/internal/client/tracking"
+)
+
+const protocolGRPC = "grpc"
+
+// grpcExecutor calls a method of grpc scenario, the connection is shared by all threads.
+type grpcExecutor struct {
+	invoker *grpcclient.Invoker
+}
+
+func newGRPCExecutor(_ HTTPClients) Executor { //nolint:ireturn
+	return &grpcExecutor{}
+}
+
+func (e *grpcExecutor) Prepare(ctx context.Context, scenario *config.Scenario) (Labels, error) {
+	if scenario.GRPCRequest == nil {
+		return Labels{}, errMissingRequest
+	}
+
+	invoker, err := grpcclient.NewInvoker(ctx, scenario.GRPCRequest)
+	if err != nil {
+		return Labels{}, err //nolint:wrapcheck
+	}
+
+	e.invoker = invoker
+
+	return Labels{
+		Method:   invoker.Method(),
+		URL:      scenario.GRPCRequest.Target,
+		Protocol: protocolGRPC,
+	}, nil
+}
+
+func (e *grpcExecutor) Execute(ctx context.Context, _ *Request) (*Response, error) {
+	startTime := time.Now()
+
+	resp := e.invoker.Call(ctx)
+
+	latency := time.Since(startTime)
+
+	if resp.Failed() {
+		return nil, resp.Err()
+	}
+
+	return &Response{
+		Code:    resp.Code.String(),
+		Latency: latency,
+		Bytes:   grpcBytes(resp),
+		Value:   resp,
+	}, nil
+}
+
+func (e *grpcExecutor) Check(checks []*config.Check, resp *Response) ([]checker.Result, bool) {
+	grpcResponse, _ := resp.Value.(*grpcclient.Response)
+
+	return checker.New(checks).ValidateGRPC(grpcResponse)
+}
+
+func (e *grpcExecutor) Close() error {
+	if e.invoker == nil {
+		return nil
+	}
+
+	return e.invoker.Close() //nolint:wrapcheck
+}
+
+// grpcBytes counts sizes of encoded messages and metadata.
+func grpcBytes(resp *grpcclient.Response) tracking.Bytes {
+	bytes := tracking.Bytes{
+		SentBody:            resp.SentBytes,
+		ReceivedBody:        resp.ReceivedBytes,
+		ReceivedBodyDecoded: resp.ReceivedBytes,
+	}
+
+	for _, md := range []map[string][]string{resp.Header, resp.Trailer} {
+		for key, values := range md {
+			for _, value := range values {
+				bytes.ReceivedHeaders += int64(len(key) + len(value))
+			}
+		}
+	}
+
+	return bytes
+}
@@ -0,0 +1,328 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/lameaux/bro/internal/client/checker"
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/lameaux/bro/internal/client/extractor"
+	"github.com/lameaux/bro/internal/client/tracking"
+	"github.com/rs/zerolog/log"
+)
+
+// httpExecutor sends requests of http and graphql scenarios.
+type httpExecutor struct {
+	httpClients HTTPClients
+	scenario    *config.Scenario
+	request     config.HTTPRequest
+	body        *payload
+	graphQL     bool
+	// bufferBody keeps response body in memory for checks, otherwise it is discarded
+	bufferBody bool
+	script     *script
+}
+
+// httpResult is a value of http responses, stream is set for streaming responses and script for scenarios with script.
+type httpResult struct {
+	response *http.Response
+	stream   *streamResult
+	script   *scriptResult
+}
+
+func newHTTPExecutor(httpClients HTTPClients) Executor { //nolint:ireturn
+	return &httpExecutor{httpClients: httpClients}
+}
+
+func (e *httpExecutor) Prepare(_ context.Context, scenario *config.Scenario) (Labels, error) {
+	e.scenario = scenario
+	e.request = scenario.HTTPRequest
+	e.graphQL = scenario.GraphQLRequest != nil
+
+	labels := Labels{
+		Method: e.request.Method(),
+		URL:    e.request.URL,
+	}
+
+	if e.graphQL {
+		request, operation, err := graphQLRequest(scenario.GraphQLRequest, e.request)
+		if err != nil {
+			return labels, err
+		}
+
+		e.request = request
+
+		labels.Method = request.Method()
+		labels.URL = request.URL
+
+		if operation != "" {
+			labels.URL = operation
+		}
+	}
+
+	if e.request.Stream != nil {
+		if err := validateStreamFormat(e.request.Stream.Format()); err != nil {
+			return labels, err
+		}
+	}
+
+	body, err := newPayload(&e.request)
+	if err != nil {
+		return labels, err
+	}
+
+	if e.graphQL {
+		body.contentType = contentTypeJSON
+		body.jsonTemplate = true
+	}
+
+	if scenario.Script != nil {
+		if e.script, err = newScript(scenario.Script); err != nil {
+			return labels, fmt.Errorf("invalid script: %w", err)
+		}
+	}
+
+	e.body = body
+	e.bufferBody = slices.ContainsFunc(scenario.Checks, func(check *config.Check) bool {
+		return check.Type == checker.TypeHTTPBody
+	})
+
+	return labels, nil
+}
+
+func (e *httpExecutor) Execute(ctx context.Context, req *Request) (*Response, error) {
+	var vm *scriptVM
+
+	if e.script != nil {
+		var err error
+		if vm, err = e.script.vm(ctx, req.ThreadID, req.Vars); err != nil {
+			return nil, err
+		}
+
+		vm.begin()
+	}
+
+	trace := &requestTrace{}
+
+	// deferred first to be called after response body is closed
+	defer e.httpClients.RequestDone(req.ThreadID)
+
+	httpReq, err := e.newRequest(httptrace.WithClientTrace(ctx, trace.clientTrace()), vm, req.Vars)
+	if err != nil {
+		return nil, err
+	}
+
+	// latency does not include beforeRequest hook of script
+	startTime := time.Now()
+
+	resp, err := e.httpClients.Client(req.ThreadID).Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	response := &Response{
+		Code:     strconv.Itoa(resp.StatusCode),
+		Protocol: resp.Proto,
+		Latency:  time.Since(startTime),
+	}
+
+	receivedHeaders := responseHeaderSize(resp)
+	body := newResponseBody(resp)
+
+	result := &httpResult{response: resp}
+
+	// stream is read first to time its events, body is kept for checks and script if they need it
+	if e.request.Stream != nil {
+		result.stream, err = e.readStream(resp, vm)
+		if err != nil {
+			return response, err
+		}
+	}
+
+	switch {
+	case e.graphQL:
+		if err = readGraphQLResponse(resp); err != nil {
+			return response, err
+		}
+	case e.bufferBody:
+		if _, err = bufferResponseBody(resp); err != nil {
+			return response, err
+		}
+	}
+
+	e.extractVars(req, resp)
+
+	if vm != nil && vm.afterResponse != nil {
+		data, err := bufferResponseBody(resp)
+		if err != nil {
+			return response, err
+		}
+
+		if err = vm.runAfterResponse(ctx, resp, data, req.Vars); err != nil {
+			return response, err
+		}
+	}
+
+	bodyDone, err := body.drain()
+	if err != nil {
+		return response, err
+	}
+
+	response.Bytes = tracking.Bytes{
+		SentHeaders:         trace.sentHeaderBytes(resp.Request),
+		SentBody:            max(resp.Request.ContentLength, 0),
+		ReceivedHeaders:     receivedHeaders,
+		ReceivedBody:        body.wire.n,
+		ReceivedBodyDecoded: body.decoded.n,
+	}
+	response.Timings = trace.timings(bodyDone)
+
+	if result.stream != nil {
+		result.stream.addTimings(response.Timings, trace, bodyDone)
+		response.Stream = &result.stream.info
+		response.Checks = append(response.Checks, result.stream.checks...)
+		response.CheckResults = append(response.CheckResults, result.stream.checkResults...)
+	}
+
+	if vm != nil {
+		result.script = vm.result
+		response.Metrics = vm.result.metrics
+		response.Checks = append(response.Checks, vm.result.checks...)
+		response.CheckResults = append(response.CheckResults, vm.result.results...)
+	}
+
+	response.Value = result
+
+	return response, nil
+}
+
+// Check validates response with scenario checks, streaming responses also fail if any of stream checks fails,
+// and responses of scenarios with script if any of bro.check calls fails.
+func (e *httpExecutor) Check(checks []*config.Check, resp *Response) ([]checker.Result, bool) {
+	result, _ := resp.Value.(*httpResult)
+
+	checkResults, success := checker.New(checks).Validate(result.response)
+
+	if stream := result.stream; stream != nil {
+		success = success && stream.success
+	}
+
+	if script := result.script; script != nil {
+		success = success && script.passed()
+	}
+
+	return checkResults, success
+}
+
+// readStream reads events of a streaming response, it tees the body into a buffer
+// if the body is used by graphql, httpBody checks or afterResponse hook.
+func (e *httpExecutor) readStream(resp *http.Response, vm *scriptVM) (*streamResult, error) {
+	if !e.graphQL && !e.bufferBody && (vm == nil || vm.afterResponse == nil) {
+		return readStream(e.request.Stream, resp.Body)
+	}
+
+	var buf bytes.Buffer
+
+	result, err := readStream(e.request.Stream, io.TeeReader(resp.Body, &buf))
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(&buf)
+
+	return result, nil
+}
+
+// newRequest builds a request with variables expanded, it is changed by beforeRequest hook of script.
+func (e *httpExecutor) newRequest(ctx context.Context, vm *scriptVM, vars map[string]string) (*http.Request, error) {
+	target, _ := e.request.Target()
+
+	request := &scriptRequest{
+		method:  e.request.Method(),
+		url:     extractor.Expand(target, vars),
+		headers: e.headers(),
+		body:    e.body.text(vars),
+	}
+
+	body, err := e.body.reader(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if vm != nil && vm.beforeRequest != nil {
+		inline := request.body
+
+		if err = vm.runBeforeRequest(ctx, request, vars); err != nil {
+			return nil, err
+		}
+
+		if request.body != nil && (inline == nil || *request.body != *inline) {
+			if body, err = e.body.textReader(*request.body); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, request.method, request.url, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	for name, value := range request.headers {
+		req.Header.Set(name, value)
+	}
+
+	return req, nil
+}
+
+// headers are set on every request, scripts can change them in beforeRequest.
+func (e *httpExecutor) headers() map[string]string {
+	headers := make(map[string]string)
+
+	// decompression is handled by runner to measure response size on the wire
+	if acceptEncoding := e.request.AcceptEncodingHeader(); acceptEncoding != "" {
+		headers["Accept-Encoding"] = acceptEncoding
+	}
+
+	if e.body != nil {
+		if e.body.contentType != "" {
+			headers["Content-Type"] = e.body.contentType
+		}
+
+		if e.body.compress != "" {
+			headers["Content-Encoding"] = e.body.compress
+		}
+	}
+
+	return headers
+}
+
+// extractVars saves values found in response to thread variables.
+func (e *httpExecutor) extractVars(req *Request, resp *http.Response) {
+	if len(e.scenario.Extract) == 0 {
+		return
+	}
+
+	values, err := extractor.New(e.scenario.Extract).Extract(resp)
+	if err != nil {
+		log.Warn().
+			Str("scenario", e.scenario.Name).
+			Int("threadID", req.ThreadID).
+			Err(err).
+			Msg("failed to extract values")
+
+		return
+	}
+
+	for name, value := range values {
+		req.Vars[name] = value
+	}
+}
@@ -3,7 +3,6 @@ package runner
 import (
 	"context"
 	"errors"
-	"net/http"
 	"time"
 
 	"github.com/lameaux/bro/internal/client/checker"
@@ -20,7 +19,6 @@ var (
 
 func (r *Runner) makeLogEvent(
 	ctx context.Context,
-	response *http.Response,
 	latency time.Duration,
 ) (*zerolog.Event, error) {
 	scenarioID, ok := ctx.Value(contextKey("scenarioID")).(int)
@@ -42,9 +40,6 @@ func (r *Runner) makeLogEvent(
 					Int("scenarioID", scenarioID).
 					Int("threadID", threadID).
 					Int("msgID", msgID).
-					Str("method", r.scenario.HTTPRequest.Method()).
-					Str("url", r.scenario.HTTPRequest.URL).
-					Int("code", response.StatusCode).
 					Int64("latency", latency.Milliseconds())
 
 	return logEvent, nil
@@ -52,19 +47,37 @@ func (r *Runner) makeLogEvent(
 
 func (r *Runner) logCheckResults(
 	ctx context.Context,
-	response *http.Response,
-	latency time.Duration,
+	resp *Response,
 	checks []*config.Check,
 	results []checker.Result,
 	success bool,
 ) {
-	logEvent, err := r.makeLogEvent(ctx, response, latency)
+	logEvent, err := r.makeLogEvent(ctx, resp.Latency)
 	if err != nil {
 		log.Warn().Err(err).Msg("failed to log check results")
 
 		return
 	}
 
+	method := r.labels.Method
+	if resp.Method != "" {
+		method = resp.Method
+	}
+
+	logEvent.
+		Str("method", method).
+		Str("url", r.labels.URL).
+		Str("code", resp.Code)
+
+	logChecks(logEvent, checks, results, success)
+}
+
+func logChecks(
+	logEvent *zerolog.Event,
+	checks []*config.Check,
+	results []checker.Result,
+	success bool,
+) {
 	checkResults := zerolog.Arr()
 
 	for i, check := range checks {
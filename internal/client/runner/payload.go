@@ -0,0 +1,234 @@
+package runner
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/lameaux/bro/internal/client/extractor"
+)
+
+const (
+	contentTypeForm        = "application/x-www-form-urlencoded"
+	contentTypeOctetStream = "application/octet-stream"
+)
+
+var errMultipleBodies = errors.New("only one of body, bodyFile, bodyBase64, form and multipart can be set")
+
+// payload is a request body prepared once per scenario, requests read it without copying.
+type payload struct {
+	data        []byte
+	contentType string
+	compress    string
+	// template is an inline body, which is expanded with thread variables and compressed per request
+	template *string
+	// jsonTemplate escapes values of variables, as they are expanded inside JSON strings, e.g. of graphql body
+	jsonTemplate bool
+}
+
+func newPayload(request *config.HTTPRequest) (*payload, error) {
+	if err := validateRequest(request); err != nil {
+		return nil, err
+	}
+
+	p := &payload{compress: request.Compress}
+
+	var err error
+
+	switch {
+	case request.BodyRaw != nil:
+		p.template = request.BodyRaw
+
+		return p, nil
+	case request.BodyFile != "":
+		p.data, err = os.ReadFile(request.BodyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read body file: %w", err)
+		}
+
+		p.contentType = contentTypeByFileName(request.BodyFile)
+	case request.BodyBase64 != "":
+		p.data, err = base64.StdEncoding.DecodeString(request.BodyBase64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode base64 body: %w", err)
+		}
+
+		p.contentType = contentTypeOctetStream
+	case len(request.Form) > 0:
+		values := make(url.Values, len(request.Form))
+		for name, value := range request.Form {
+			values.Set(name, value)
+		}
+
+		p.data = []byte(values.Encode())
+		p.contentType = contentTypeForm
+	case len(request.Multipart) > 0:
+		p.data, p.contentType, err = multipartBody(request.Multipart)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, nil //nolint:nilnil
+	}
+
+	if p.compress != "" {
+		if p.data, err = compressBody(p.compress, p.data); err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+func validateRequest(request *config.HTTPRequest) error {
+	switch request.Compress {
+	case "", encodingGzip, encodingBrotli, encodingZstd:
+	default:
+		return fmt.Errorf("%w: %s", errUnknownEncoding, request.Compress)
+	}
+
+	bodies := 0
+
+	for _, set := range []bool{
+		request.BodyRaw != nil,
+		request.BodyFile != "",
+		request.BodyBase64 != "",
+		len(request.Form) > 0,
+		len(request.Multipart) > 0,
+	} {
+		if set {
+			bodies++
+		}
+	}
+
+	if bodies > 1 {
+		return errMultipleBodies
+	}
+
+	return nil
+}
+
+func multipartBody(fields []*config.MultipartField) ([]byte, string, error) {
+	var buf bytes.Buffer
+
+	writer := multipart.NewWriter(&buf)
+
+	for _, field := range fields {
+		if field.File == "" {
+			if err := writer.WriteField(field.Name, field.Value); err != nil {
+				return nil, "", fmt.Errorf("failed to write multipart field: %w", err)
+			}
+
+			continue
+		}
+
+		if err := writeMultipartFile(writer, field); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", fmt.Errorf("failed to write multipart body: %w", err)
+	}
+
+	return buf.Bytes(), writer.FormDataContentType(), nil
+}
+
+func writeMultipartFile(writer *multipart.Writer, field *config.MultipartField) error {
+	data, err := os.ReadFile(field.File)
+	if err != nil {
+		return fmt.Errorf("failed to read multipart file: %w", err)
+	}
+
+	fileName := field.FileName
+	if fileName == "" {
+		fileName = filepath.Base(field.File)
+	}
+
+	contentType := field.ContentType
+	if contentType == "" {
+		contentType = contentTypeByFileName(field.File)
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", mime.FormatMediaType("form-data", map[string]string{
+		"name":     field.Name,
+		"filename": fileName,
+	}))
+	header.Set("Content-Type", contentType)
+
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return fmt.Errorf("failed to create multipart file: %w", err)
+	}
+
+	if _, err = part.Write(data); err != nil {
+		return fmt.Errorf("failed to write multipart file: %w", err)
+	}
+
+	return nil
+}
+
+func contentTypeByFileName(fileName string) string {
+	if contentType := mime.TypeByExtension(filepath.Ext(fileName)); contentType != "" {
+		return contentType
+	}
+
+	return contentTypeOctetStream
+}
+
+// reader returns request body, nil payload means no body.
+func (p *payload) reader(vars map[string]string) (io.Reader, error) {
+	if p == nil {
+		return nil, nil
+	}
+
+	if p.template == nil {
+		return bytes.NewReader(p.data), nil
+	}
+
+	return p.textReader(p.expand(vars))
+}
+
+// text returns an inline body expanded with thread variables, it is nil for other bodies.
+func (p *payload) text(vars map[string]string) *string {
+	if p == nil || p.template == nil {
+		return nil
+	}
+
+	body := p.expand(vars)
+
+	return &body
+}
+
+func (p *payload) expand(vars map[string]string) string {
+	if p.jsonTemplate {
+		return extractor.ExpandJSON(*p.template, vars)
+	}
+
+	return extractor.Expand(*p.template, vars)
+}
+
+// textReader returns a text body, e.g. set by a script, compressed like inline bodies.
+func (p *payload) textReader(body string) (io.Reader, error) {
+	if p == nil || p.compress == "" {
+		return strings.NewReader(body), nil
+	}
+
+	compressed, err := compressBody(p.compress, []byte(body))
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(compressed), nil
+}
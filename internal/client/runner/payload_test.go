@@ -0,0 +1,165 @@
+package runner
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lameaux/bro/internal/client/config"
+)
+
+func readPayload(t *testing.T, p *payload, vars map[string]string) string {
+	t.Helper()
+
+	reader, err := p.reader(vars)
+	if err != nil {
+		t.Fatalf("failed to create reader: %v", err)
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("failed to read payload: %v", err)
+	}
+
+	return string(data)
+}
+
+func TestNewPayload(t *testing.T) {
+	t.Parallel()
+
+	bodyFile := filepath.Join(t.TempDir(), "fixture.json")
+	if err := os.WriteFile(bodyFile, []byte(`{"id": 1}`), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	raw := "id=${id}"
+
+	tests := []struct {
+		name        string
+		request     config.HTTPRequest
+		body        string
+		contentType string
+	}{
+		{name: "raw", request: config.HTTPRequest{BodyRaw: &raw}, body: "id=42"},
+		{name: "file", request: config.HTTPRequest{BodyFile: bodyFile}, body: `{"id": 1}`, contentType: "application/json"},
+		{
+			name:        "base64",
+			request:     config.HTTPRequest{BodyBase64: "AAEC"},
+			body:        "\x00\x01\x02",
+			contentType: contentTypeOctetStream,
+		},
+		{
+			name:        "form",
+			request:     config.HTTPRequest{Form: map[string]string{"b": "2", "a": "1 2"}},
+			body:        "a=1+2&b=2",
+			contentType: contentTypeForm,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			p, err := newPayload(&tt.request)
+			if err != nil {
+				t.Fatalf("failed to create payload: %v", err)
+			}
+
+			if body := readPayload(t, p, map[string]string{"id": "42"}); body != tt.body {
+				t.Errorf("body equals %q; expected %q", body, tt.body)
+			}
+
+			if p.contentType != tt.contentType {
+				t.Errorf("content type equals %s; expected %s", p.contentType, tt.contentType)
+			}
+		})
+	}
+}
+
+func TestNewPayloadMultipart(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "avatar.png")
+	if err := os.WriteFile(file, []byte("png"), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	p, err := newPayload(&config.HTTPRequest{
+		Multipart: []*config.MultipartField{
+			{Name: "user", Value: "bro"},
+			{Name: "avatar", File: file},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create payload: %v", err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(p.contentType)
+	if err != nil || mediaType != "multipart/form-data" {
+		t.Fatalf("content type equals %s; expected multipart/form-data", p.contentType)
+	}
+
+	form, err := multipart.NewReader(strings.NewReader(readPayload(t, p, nil)), params["boundary"]).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatalf("failed to parse multipart body: %v", err)
+	}
+
+	if user := form.Value["user"]; len(user) != 1 || user[0] != "bro" {
+		t.Errorf("user equals %v; expected bro", user)
+	}
+
+	avatars := form.File["avatar"]
+	if len(avatars) != 1 {
+		t.Fatalf("expected one file, got %d", len(avatars))
+	}
+
+	if avatars[0].Filename != "avatar.png" || avatars[0].Header.Get("Content-Type") != "image/png" {
+		t.Errorf("file equals %s (%s); expected avatar.png (image/png)",
+			avatars[0].Filename, avatars[0].Header.Get("Content-Type"))
+	}
+}
+
+func TestNewPayloadCompressed(t *testing.T) {
+	t.Parallel()
+
+	p, err := newPayload(&config.HTTPRequest{Form: map[string]string{"a": "1"}, Compress: encodingGzip})
+	if err != nil {
+		t.Fatalf("failed to create payload: %v", err)
+	}
+
+	decoded, err := io.ReadAll(&decodingReader{src: strings.NewReader(readPayload(t, p, nil)), encoding: encodingGzip})
+	if err != nil {
+		t.Fatalf("failed to decompress payload: %v", err)
+	}
+
+	if string(decoded) != "a=1" {
+		t.Errorf("body equals %q; expected a=1", decoded)
+	}
+}
+
+func TestNewPayloadInvalid(t *testing.T) {
+	t.Parallel()
+
+	raw := "body"
+
+	if _, err := newPayload(&config.HTTPRequest{BodyRaw: &raw, BodyBase64: "AAEC"}); !errors.Is(err, errMultipleBodies) {
+		t.Errorf("err equals %v; expected %v", err, errMultipleBodies)
+	}
+
+	if _, err := newPayload(&config.HTTPRequest{Compress: "deflate"}); !errors.Is(err, errUnknownEncoding) {
+		t.Errorf("err equals %v; expected %v", err, errUnknownEncoding)
+	}
+
+	if _, err := newPayload(&config.HTTPRequest{BodyFile: "missing.json"}); err == nil {
+		t.Error("expected error for missing body file")
+	}
+
+	if p, err := newPayload(&config.HTTPRequest{}); p != nil || err != nil {
+		t.Errorf("expected no payload, got %v, %v", p, err)
+	}
+}
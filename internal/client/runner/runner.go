@@ -3,7 +3,8 @@ package runner
 import (
 	"context"
 	"fmt"
-	"net/http"
+	"io"
+	"sync"
 	"time"
 
 	"github.com/lameaux/bro/internal/client/config"
@@ -15,28 +16,45 @@ import (
 type contextKey string
 
 type Runner struct {
-	httpClient *http.Client
-	scenarioID int
-	scenario   *config.Scenario
-	listeners  []StatListener
+	httpClients HTTPClients
+	scenarioID  int
+	scenario    *config.Scenario
+	checks      *thresholds.CheckCounters
+	listeners   []StatListener
+
+	executor Executor
+	labels   Labels
+	vars     sync.Map // by threadID
 }
 
 func New(
-	httpClient *http.Client,
+	httpClients HTTPClients,
 	scenarioID int,
 	scenario *config.Scenario,
+	checks *thresholds.CheckCounters,
 	listeners []StatListener,
 ) *Runner {
 	return &Runner{
-		httpClient: httpClient,
-		scenarioID: scenarioID,
-		scenario:   scenario,
-		listeners:  listeners,
+		httpClients: httpClients,
+		scenarioID:  scenarioID,
+		scenario:    scenario,
+		checks:      checks,
+		listeners:   listeners,
 	}
 }
 
 func (r *Runner) Run(ctx context.Context) error {
-	thresholds.AddScenario(r.scenario)
+	if err := r.prepare(ctx); err != nil {
+		return err
+	}
+
+	if closer, ok := r.executor.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	if session, ok := r.executor.(SessionExecutor); ok && session.Held() > 0 {
+		return r.runHeldSessions(ctx, session)
+	}
 
 	if len(r.scenario.Stages) > 0 {
 		return r.runVariableRate(ctx)
@@ -45,6 +63,32 @@ func (r *Runner) Run(ctx context.Context) error {
 	return r.runConstantRate(ctx)
 }
 
+// prepare creates an executor by scenario type.
+func (r *Runner) prepare(ctx context.Context) error {
+	scenarioType := r.scenario.Type()
+
+	executor, err := newExecutor(scenarioType, r.httpClients)
+	if err != nil {
+		return err
+	}
+
+	switch executor.(type) {
+	case RequestExecutor, SessionExecutor:
+	default:
+		return fmt.Errorf("invalid %s scenario: %w", scenarioType, errInvalidExecutor)
+	}
+
+	labels, err := executor.Prepare(ctx, r.scenario)
+	if err != nil {
+		return fmt.Errorf("invalid %s scenario: %w", scenarioType, err)
+	}
+
+	r.executor = executor
+	r.labels = labels
+
+	return nil
+}
+
 func (r *Runner) runConstantRate(ctx context.Context) error {
 	log.Info().Dict(
 		"scenario",
@@ -55,6 +99,8 @@ func (r *Runner) runConstantRate(ctx context.Context) error {
 			Str("duration", r.scenario.Duration().Round(time.Millisecond).String()),
 	).Msg("running constant rate scenario")
 
+	r.trackStage("", r.scenario.Rps(), r.scenario.Rps(), r.scenario.Threads(), r.scenario.Duration())
+
 	return r.runStage(
 		ctx,
 		r.scenario.Threads(),
@@ -85,8 +131,12 @@ func (r *Runner) runVariableRate(ctx context.Context) error {
 				Str("duration", stage.Duration().Round(time.Millisecond).String()),
 		).Msg("running stage")
 
+		name := stageName(stageID, stage)
+
+		r.trackStage(name, previousRPS, stage.Rps(), stage.Threads(), stage.Duration())
+
 		err := r.runStage(
-			ctx,
+			context.WithValue(ctx, contextKey("stage"), name),
 			stage.Threads(),
 			stage.Duration(),
 			previousRPS,
@@ -128,3 +178,11 @@ func (r *Runner) runStage(
 
 	return nil
 }
+
+func stageName(stageID int, stage *config.Stage) string {
+	if stage.Name != "" {
+		return stage.Name
+	}
+
+	return fmt.Sprintf("#%d", stageID+1)
+}
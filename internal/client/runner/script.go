@@ -0,0 +1,283 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/dop251/goja"
+	"github.com/lameaux/bro/internal/client/checker"
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/lameaux/bro/internal/client/tracking"
+)
+
+const (
+	hookInit          = "init"
+	hookBeforeRequest = "beforeRequest"
+	hookAfterResponse = "afterResponse"
+
+	scriptName = "script.js"
+)
+
+var (
+	errMissingScript   = errors.New("one of source and file must be set")
+	errMultipleScripts = errors.New("only one of source and file can be set")
+	errScriptCheck     = errors.New("script check failed")
+)
+
+// script is compiled once per scenario, its hooks run in a VM per sender thread,
+// as goja runtime must not be used by several goroutines.
+type script struct {
+	program *goja.Program
+	vms     sync.Map // *scriptVM by threadID
+}
+
+// scriptVM keeps state of a thread returned by init, and results of bro.check and bro.metric of a request.
+type scriptVM struct {
+	rt            *goja.Runtime
+	beforeRequest goja.Callable
+	afterResponse goja.Callable
+	state         goja.Value
+
+	result *scriptResult
+}
+
+// scriptResult is reported by hooks of a request, checks are counted for thresholds like stream checks.
+type scriptResult struct {
+	checks  []*config.Check
+	results []checker.Result
+	metrics map[string]int64
+}
+
+// scriptRequest is passed to beforeRequest, body is nil for requests without inline body.
+type scriptRequest struct {
+	method  string
+	url     string
+	headers map[string]string
+	body    *string
+}
+
+func newScript(conf *config.Script) (*script, error) {
+	source, name := conf.Source, scriptName
+
+	switch {
+	case source != "" && conf.File != "":
+		return nil, errMultipleScripts
+	case conf.File != "":
+		data, err := os.ReadFile(conf.File)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read script file: %w", err)
+		}
+
+		source, name = string(data), conf.File
+	case source == "":
+		return nil, errMissingScript
+	}
+
+	program, err := goja.Compile(name, source, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile script: %w", err)
+	}
+
+	return &script{program: program}, nil
+}
+
+func (s *script) newVM() (*scriptVM, error) {
+	vm := &scriptVM{rt: goja.New()}
+
+	bro := vm.rt.NewObject()
+	_ = bro.Set("check", vm.check)
+	_ = bro.Set("metric", vm.metric)
+	vm.rt.Set("bro", bro)
+
+	if _, err := vm.rt.RunProgram(s.program); err != nil {
+		return nil, fmt.Errorf("failed to run script: %w", err)
+	}
+
+	vm.beforeRequest, _ = goja.AssertFunction(vm.rt.Get(hookBeforeRequest))
+	vm.afterResponse, _ = goja.AssertFunction(vm.rt.Get(hookAfterResponse))
+
+	return vm, nil
+}
+
+// vm returns a VM of the thread, a new VM calls init with thread id and variables.
+func (s *script) vm(ctx context.Context, threadID int, vars map[string]string) (*scriptVM, error) {
+	if value, ok := s.vms.Load(threadID); ok {
+		vm, _ := value.(*scriptVM)
+
+		return vm, nil
+	}
+
+	vm, err := s.newVM()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", tracking.ErrScript, err)
+	}
+
+	vm.state = vm.rt.NewObject()
+
+	if init, ok := goja.AssertFunction(vm.rt.Get(hookInit)); ok {
+		thread := vm.rt.NewObject()
+		_ = thread.Set("id", threadID)
+		_ = thread.Set("vars", vm.strings(vars))
+
+		state, err := vm.call(ctx, init, thread)
+		if err != nil {
+			return nil, err
+		}
+
+		if !goja.IsUndefined(state) && !goja.IsNull(state) {
+			vm.state = state
+		}
+
+		readStrings(thread.Get("vars"), vars)
+	}
+
+	s.vms.Store(threadID, vm)
+
+	return vm, nil
+}
+
+// begin resets results of the previous request.
+func (vm *scriptVM) begin() {
+	vm.result = &scriptResult{}
+}
+
+func (vm *scriptVM) runBeforeRequest(ctx context.Context, req *scriptRequest, vars map[string]string) error {
+	obj := vm.rt.NewObject()
+	_ = obj.Set("method", req.method)
+	_ = obj.Set("url", req.url)
+	_ = obj.Set("headers", vm.strings(req.headers))
+	_ = obj.Set("vars", vm.strings(vars))
+
+	if req.body != nil {
+		_ = obj.Set("body", *req.body)
+	} else {
+		_ = obj.Set("body", goja.Null())
+	}
+
+	if _, err := vm.call(ctx, vm.beforeRequest, obj, vm.state); err != nil {
+		return err
+	}
+
+	req.method = obj.Get("method").String()
+	req.url = obj.Get("url").String()
+	req.headers = make(map[string]string)
+	readStrings(obj.Get("headers"), req.headers)
+	readStrings(obj.Get("vars"), vars)
+
+	req.body = nil
+
+	if body := obj.Get("body"); body != nil && !goja.IsUndefined(body) && !goja.IsNull(body) {
+		text := body.String()
+		req.body = &text
+	}
+
+	return nil
+}
+
+func (vm *scriptVM) runAfterResponse(
+	ctx context.Context,
+	resp *http.Response,
+	body []byte,
+	vars map[string]string,
+) error {
+	headers := make(map[string]string, len(resp.Header))
+	for name := range resp.Header {
+		headers[name] = resp.Header.Get(name)
+	}
+
+	obj := vm.rt.NewObject()
+	_ = obj.Set("status", resp.StatusCode)
+	_ = obj.Set("headers", vm.strings(headers))
+	_ = obj.Set("body", string(body))
+	_ = obj.Set("vars", vm.strings(vars))
+
+	if _, err := vm.call(ctx, vm.afterResponse, obj, vm.state); err != nil {
+		return err
+	}
+
+	readStrings(obj.Get("vars"), vars)
+
+	return nil
+}
+
+// call runs a hook, it is interrupted when ctx is canceled, e.g. by an endless loop at the end of scenario.
+func (vm *scriptVM) call(ctx context.Context, hook goja.Callable, args ...goja.Value) (goja.Value, error) {
+	stop := context.AfterFunc(ctx, func() {
+		vm.rt.Interrupt(ctx.Err())
+	})
+	defer stop()
+
+	value, err := hook(goja.Undefined(), args...)
+	if err != nil {
+		vm.rt.ClearInterrupt()
+
+		return nil, fmt.Errorf("%w: %w", tracking.ErrScript, err)
+	}
+
+	return value, nil
+}
+
+// check is bro.check(name, passed), name is a check type for thresholds.
+func (vm *scriptVM) check(name string, passed bool) {
+	if vm.result == nil {
+		return
+	}
+
+	result := checker.Result{Actual: name, Pass: passed}
+	if !passed {
+		result.Error = fmt.Errorf("%w: %s", errScriptCheck, name)
+	}
+
+	vm.result.checks = append(vm.result.checks, &config.Check{Type: name})
+	vm.result.results = append(vm.result.results, result)
+}
+
+// metric is bro.metric(name, value), values are summed up per scenario.
+func (vm *scriptVM) metric(name string, value int64) {
+	if vm.result == nil {
+		return
+	}
+
+	if vm.result.metrics == nil {
+		vm.result.metrics = make(map[string]int64)
+	}
+
+	vm.result.metrics[name] += value
+}
+
+func (vm *scriptVM) strings(values map[string]string) *goja.Object {
+	obj := vm.rt.NewObject()
+
+	for name, value := range values {
+		_ = obj.Set(name, value)
+	}
+
+	return obj
+}
+
+// readStrings copies properties of a JS object as strings, e.g. variables updated by a hook.
+func readStrings(value goja.Value, values map[string]string) {
+	obj, ok := value.(*goja.Object)
+	if !ok {
+		return
+	}
+
+	for _, name := range obj.Keys() {
+		values[name] = obj.Get(name).String()
+	}
+}
+
+// passed reports if all checks of a script passed.
+func (r *scriptResult) passed() bool {
+	for _, result := range r.results {
+		if !result.Pass {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,229 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/lameaux/bro/internal/client/thresholds"
+	"github.com/lameaux/bro/internal/client/tracking"
+	"github.com/lameaux/bro/internal/shared/httpclient"
+)
+
+const cartScript = `
+function init(thread) {
+	thread.vars.user = "user" + thread.id;
+
+	return {added: 0};
+}
+
+function beforeRequest(req, state) {
+	if (req.vars.product) {
+		req.method = "POST";
+		req.url += "/cart";
+		req.body = JSON.stringify({product: req.vars.product, quantity: state.added + 1});
+	}
+
+	req.headers["X-User"] = req.vars.user;
+	delete req.headers["Accept-Encoding"];
+}
+
+function afterResponse(res, state) {
+	const body = JSON.parse(res.body);
+
+	if (body.products) {
+		bro.check("hasProducts", body.products.length > 0);
+		res.vars.product = body.products[0];
+
+		return;
+	}
+
+	state.added += body.quantity;
+	bro.metric("itemsAdded", body.quantity);
+	bro.check("added", res.status === 200);
+}
+`
+
+func startShopServer(t *testing.T) string {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-User") != "user0" || r.Header.Get("Accept-Encoding") != "" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		if r.Method == http.MethodPost && r.URL.Path == "/cart" {
+			body, _ := io.ReadAll(r.Body)
+			if string(body) != `{"product":"p1","quantity":1}` {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+
+			_, _ = io.WriteString(w, `{"quantity": 1}`)
+
+			return
+		}
+
+		_, _ = io.WriteString(w, `{"products": ["p1", "p2"]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	return server.URL
+}
+
+func newScriptRunner(t *testing.T, name, url, source string) (*Runner, *recordingListener) {
+	t.Helper()
+
+	clients, err := httpclient.NewClients(config.HTTPClient{})
+	if err != nil {
+		t.Fatalf("failed to create clients: %v", err)
+	}
+
+	scenario := &config.Scenario{
+		Name:        name,
+		HTTPRequest: config.HTTPRequest{URL: url},
+		Script:      &config.Script{Source: source},
+	}
+
+	listener := &recordingListener{}
+
+	r := New(clients, 0, scenario, thresholds.NewCheckCounters(), []StatListener{listener})
+	if err = r.prepare(context.Background()); err != nil {
+		t.Fatalf("failed to prepare: %v", err)
+	}
+
+	return r, listener
+}
+
+func TestProcessMessage_Script(t *testing.T) {
+	r, listener := newScriptRunner(t, "script", startShopServer(t), cartScript)
+
+	r.processMessage(context.Background(), 0, 1)
+	r.processMessage(context.Background(), 0, 2)
+
+	if len(listener.responses) != 2 || listener.successes != 2 {
+		t.Fatalf("expected successful responses, got %d failed", len(listener.failed))
+	}
+
+	if vars := r.threadVars(0); vars["user"] != "user0" || vars["product"] != "p1" {
+		t.Errorf("unexpected thread vars: %v", vars)
+	}
+
+	info := listener.responses[1]
+	if info.Code != "200" || info.Metrics["itemsAdded"] != 1 {
+		t.Errorf("unexpected request info: %+v", info)
+	}
+
+	executor, _ := r.executor.(*httpExecutor)
+
+	value, _ := executor.script.vms.Load(0)
+	if vm, _ := value.(*scriptVM); vm.state.ToObject(vm.rt).Get("added").ToInteger() != 1 {
+		t.Errorf("thread state was not updated")
+	}
+}
+
+func TestProcessMessage_ScriptCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"products": []}`)
+	}))
+	t.Cleanup(server.Close)
+
+	r, listener := newScriptRunner(t, "script check", server.URL, `
+function afterResponse(res) {
+	bro.check("hasProducts", JSON.parse(res.body).products.length > 0);
+}
+`)
+
+	r.processMessage(context.Background(), 0, 1)
+
+	if len(listener.responses) != 1 || listener.successes != 0 {
+		t.Errorf("expected response failing script check, got %d successful", listener.successes)
+	}
+}
+
+func TestProcessMessage_ScriptError(t *testing.T) {
+	r, listener := newScriptRunner(t, "script error", "http://localhost:1", `
+function beforeRequest(req) {
+	throw new Error("no products");
+}
+`)
+
+	r.processMessage(context.Background(), 0, 1)
+
+	if len(listener.failed) != 1 || listener.failed[0].ErrorCategory != tracking.ErrorScript {
+		t.Errorf("expected script error, got %+v", listener.failed)
+	}
+}
+
+func TestProcessMessage_ScriptLatency(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {}))
+	t.Cleanup(server.Close)
+
+	r, listener := newScriptRunner(t, "script latency", server.URL, `
+function beforeRequest() {
+	const start = Date.now();
+	while (Date.now() - start < 200) {}
+}
+`)
+
+	r.processMessage(context.Background(), 0, 1)
+
+	if len(listener.latencies) != 1 {
+		t.Fatalf("expected response, got %d failed", len(listener.failed))
+	}
+
+	if latency := listener.latencies[0]; latency >= 200*time.Millisecond {
+		t.Errorf("latency %v includes beforeRequest hook", latency)
+	}
+}
+
+func TestScript_Interrupt(t *testing.T) {
+	t.Parallel()
+
+	s, err := newScript(&config.Script{Source: "function init() { for (;;) {} }"})
+	if err != nil {
+		t.Fatalf("failed to create script: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	if _, err = s.vm(ctx, 0, map[string]string{}); !errors.Is(err, tracking.ErrScript) {
+		t.Errorf("expected interrupted script, got %v", err)
+	}
+}
+
+func TestNewScript(t *testing.T) {
+	t.Parallel()
+
+	for _, conf := range []*config.Script{
+		{},
+		{Source: "function init() {}", File: "hooks.js"},
+		{File: "missing.js"},
+		{Source: "function init( {"},
+	} {
+		if _, err := newScript(conf); err == nil {
+			t.Errorf("expected error for %+v", conf)
+		}
+	}
+}
+
+func TestScript_RuntimeError(t *testing.T) {
+	t.Parallel()
+
+	// the program is only compiled by newScript, it runs in a VM of a thread
+	s, err := newScript(&config.Script{Source: "undefinedFunction()"})
+	if err != nil {
+		t.Fatalf("failed to create script: %v", err)
+	}
+
+	if _, err = s.vm(context.Background(), 0, map[string]string{}); !errors.Is(err, tracking.ErrScript) {
+		t.Errorf("expected script error, got %v", err)
+	}
+}
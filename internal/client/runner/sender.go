@@ -2,12 +2,11 @@ package runner
 
 import (
 	"context"
-	"fmt"
-	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/lameaux/bro/internal/client/checker"
-	"github.com/lameaux/bro/internal/client/thresholds"
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"golang.org/x/sync/errgroup"
 )
@@ -64,61 +63,117 @@ func (r *Runner) runSender(
 }
 
 func (r *Runner) processMessage(ctx context.Context, threadID int, msgID int) {
-	ctxWithValues := context.WithValue(ctx, contextKey("scenarioID"), r.scenarioID)
-	ctxWithValues = context.WithValue(ctxWithValues, contextKey("threadID"), threadID)
-	ctxWithValues = context.WithValue(ctxWithValues, contextKey("msgID"), msgID)
+	ctxWithValues := r.messageContext(ctx, threadID, msgID)
 
-	startTime := time.Now()
+	req := &Request{
+		ThreadID: threadID,
+		MsgID:    msgID,
+		Vars:     r.threadVars(threadID),
+	}
 
-	resp, err := r.sendRequest(ctxWithValues)
+	switch executor := r.executor.(type) {
+	case SessionExecutor:
+		executor.Session(ctxWithValues, req, r.tracker(ctxWithValues))
+	case RequestExecutor:
+		r.execute(ctxWithValues, executor, req)
+	}
+}
+
+func (r *Runner) execute(ctx context.Context, executor RequestExecutor, req *Request) {
+	r.trackRequest(ctx)
+
+	resp, err := executor.Execute(ctx, req)
 	if err != nil {
 		log.Debug().
 			Int("scenarioID", r.scenarioID).
-			Int("threadID", threadID).
-			Int("msgID", msgID).
+			Int("threadID", req.ThreadID).
+			Int("msgID", req.MsgID).
 			Err(err).
-			Msg("failed to send http request")
+			Msg("request failed")
 
-		r.trackError(err)
+		r.trackError(ctx, resp, err)
 
 		return
 	}
-	defer resp.Body.Close()
 
-	latency := time.Since(startTime)
+	checkResults, success := executor.Check(r.scenario.Checks, resp)
 
-	responseChecker := checker.New(r.scenario.Checks)
-	checkResults, success := responseChecker.Validate(resp)
-
-	r.logCheckResults(
-		ctxWithValues,
-		resp,
-		latency,
-		r.scenario.Checks,
-		checkResults,
-		success,
-	)
+	r.logCheckResults(ctx, resp, r.scenario.Checks, checkResults, success)
 
-	r.trackResponse(resp, success, latency)
+	r.trackResponse(ctx, resp, success)
 
-	thresholds.UpdateScenario(r.scenario, checkResults)
+	r.checks.Update(r.scenario.Checks, checkResults)
+	r.checks.Update(resp.Checks, resp.CheckResults)
 }
 
-func (r *Runner) sendRequest(ctx context.Context) (*http.Response, error) {
-	req, err := http.NewRequestWithContext(
-		ctx,
-		r.scenario.HTTPRequest.Method(),
-		r.scenario.HTTPRequest.URL,
-		r.scenario.HTTPRequest.BodyReader(),
+// runHeldSessions keeps the number of sessions open for scenario duration.
+// Sessions are opened at rps rate, ended sessions are reopened.
+func (r *Runner) runHeldSessions(ctx context.Context, executor SessionExecutor) error {
+	held := executor.Held()
+
+	log.Info().Dict(
+		"scenario",
+		zerolog.Dict().
+			Str("name", r.scenario.Name).
+			Int("held", held).
+			Int("rps", r.scenario.Rps()).
+			Str("duration", r.scenario.Duration().Round(time.Millisecond).String()),
+	).Msg("running held sessions scenario")
+
+	r.trackStage("", r.scenario.Rps(), r.scenario.Rps(), held, r.scenario.Duration())
+
+	ctx, cancel := context.WithTimeout(ctx, r.scenario.Duration())
+	defer cancel()
+
+	opens := time.NewTicker(time.Second / time.Duration(r.scenario.Rps()))
+	defer opens.Stop()
+
+	var (
+		wg    sync.WaitGroup
+		msgID atomic.Int64
 	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
 
-	res, err := r.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+	wg.Add(held)
+
+	for threadID := 0; threadID < held; threadID++ {
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-opens.C:
+					if ctx.Err() != nil {
+						return
+					}
+
+					id := int(msgID.Add(1))
+					ctxWithValues := r.messageContext(ctx, threadID, id)
+
+					executor.Session(ctxWithValues, &Request{
+						ThreadID: threadID,
+						MsgID:    id,
+						Vars:     r.threadVars(threadID),
+						Held:     true,
+					}, r.tracker(ctxWithValues))
+				}
+			}
+		}()
 	}
 
-	return res, nil
+	wg.Wait()
+
+	return nil
+}
+
+func (r *Runner) tracker(ctx context.Context) *Tracker {
+	return &Tracker{r: r, ctx: ctx}
+}
+
+func (r *Runner) messageContext(ctx context.Context, threadID int, msgID int) context.Context {
+	ctxWithValues := context.WithValue(ctx, contextKey("scenarioID"), r.scenarioID)
+	ctxWithValues = context.WithValue(ctxWithValues, contextKey("threadID"), threadID)
+
+	return context.WithValue(ctxWithValues, contextKey("msgID"), msgID)
 }
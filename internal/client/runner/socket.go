@@ -0,0 +1,246 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/lameaux/bro/internal/client/checker"
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/lameaux/bro/internal/client/extractor"
+	"github.com/lameaux/bro/internal/client/tracking"
+)
+
+const (
+	networkTCP = "tcp"
+	networkUDP = "udp"
+
+	// socketCodeSent is reported when response is not read
+	socketCodeSent     = "sent"
+	socketCodeReceived = "received"
+
+	// maxDatagramSize fits any UDP datagram, it is also used as a read buffer of TCP responses.
+	maxDatagramSize = 64 << 10
+)
+
+var (
+	errMissingAddress   = errors.New("address must be set")
+	errMultiplePayloads = errors.New("only one of payload and payloadHex can be set")
+)
+
+// socketExecutor sends payloads over new TCP connections or as UDP datagrams.
+type socketExecutor struct {
+	network string
+	conf    *config.SocketRequest
+	dialer  *net.Dialer
+	// data is a decoded hex payload, text payload is expanded with thread variables per request
+	data []byte
+}
+
+func newSocketExecutor(network string) ExecutorFactory {
+	return func(_ HTTPClients) Executor {
+		return &socketExecutor{
+			network: network,
+			dialer:  &net.Dialer{},
+		}
+	}
+}
+
+func (e *socketExecutor) Prepare(_ context.Context, scenario *config.Scenario) (Labels, error) {
+	conf := scenario.TCPRequest
+	if e.network == networkUDP {
+		conf = scenario.UDPRequest
+	}
+
+	if conf == nil || conf.Address == "" {
+		return Labels{}, errMissingAddress
+	}
+
+	e.conf = conf
+
+	if conf.PayloadHex != "" {
+		if conf.Payload != "" {
+			return Labels{}, errMultiplePayloads
+		}
+
+		data, err := hex.DecodeString(strings.ReplaceAll(conf.PayloadHex, " ", ""))
+		if err != nil {
+			return Labels{}, fmt.Errorf("failed to decode hex payload: %w", err)
+		}
+
+		e.data = data
+	}
+
+	return Labels{
+		Method:   strings.ToUpper(e.network),
+		URL:      conf.Address,
+		Protocol: e.network,
+	}, nil
+}
+
+func (e *socketExecutor) Execute(ctx context.Context, req *Request) (*Response, error) {
+	startTime := time.Now()
+
+	result, err := e.send(ctx, e.payload(req.Vars))
+	if err != nil {
+		return nil, err
+	}
+
+	response := &Response{
+		Code:    socketCodeSent,
+		Latency: time.Since(startTime),
+		Timings: result.timings(e.network, startTime),
+		Bytes: tracking.Bytes{
+			SentBody:            result.sent,
+			ReceivedBody:        int64(len(result.response)),
+			ReceivedBodyDecoded: int64(len(result.response)),
+		},
+		Value: result.response,
+	}
+
+	if e.conf.Response != nil {
+		response.Code = socketCodeReceived
+	}
+
+	return response, nil
+}
+
+func (e *socketExecutor) Check(checks []*config.Check, resp *Response) ([]checker.Result, bool) {
+	data, _ := resp.Value.([]byte)
+
+	return checker.New(checks).ValidateSocket(data)
+}
+
+func (e *socketExecutor) payload(vars map[string]string) []byte {
+	if e.data != nil {
+		return e.data
+	}
+
+	return []byte(extractor.Expand(e.conf.Payload, vars))
+}
+
+// socketResult is a response to a payload, response is empty unless it is read.
+type socketResult struct {
+	response                    []byte
+	connected, wrote, firstByte time.Time
+	sent                        int64
+}
+
+func (e *socketExecutor) send(ctx context.Context, payload []byte) (*socketResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, e.conf.Timeout())
+	defer cancel()
+
+	result := &socketResult{}
+
+	conn, err := e.dialer.DialContext(ctx, e.network, e.conf.Address)
+	if err != nil {
+		return result, fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Close()
+
+	result.connected = time.Now()
+
+	deadline, _ := ctx.Deadline()
+	_ = conn.SetDeadline(deadline)
+
+	// unblock reads when scenario ends
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	n, err := conn.Write(payload)
+	result.sent = int64(n)
+
+	if err != nil {
+		return result, fmt.Errorf("failed to send payload: %w", err)
+	}
+
+	result.wrote = time.Now()
+
+	if e.conf.Response == nil {
+		return result, nil
+	}
+
+	err = readSocketResponse(conn, e.network, e.conf.Response, result)
+	if err != nil && ctx.Err() != nil {
+		err = fmt.Errorf("%w: %w", context.Cause(ctx), err)
+	}
+
+	return result, err
+}
+
+// readSocketResponse reads until delimiter or length. If neither is set, it reads a single datagram of udp,
+// or tcp until end of connection, or until timeout when any bytes were received.
+func readSocketResponse(conn net.Conn, network string, conf *config.SocketResponse, result *socketResult) error {
+	buf := make([]byte, maxDatagramSize)
+	delimiter := []byte(conf.Delimiter)
+	untilEnd := conf.Length == 0 && len(delimiter) == 0
+
+	for {
+		n, err := conn.Read(buf)
+		if n > 0 {
+			if result.firstByte.IsZero() {
+				result.firstByte = time.Now()
+			}
+
+			result.response = append(result.response, buf[:n]...)
+
+			if conf.Length > 0 && len(result.response) >= conf.Length {
+				result.response = result.response[:conf.Length]
+
+				return nil
+			}
+
+			if index := bytes.Index(result.response, delimiter); len(delimiter) > 0 && index >= 0 {
+				result.response = result.response[:index+len(delimiter)]
+
+				return nil
+			}
+
+			if untilEnd && network == networkUDP {
+				return nil
+			}
+		}
+
+		if err != nil {
+			if untilEnd && isEndOfResponse(err, len(result.response) > 0) {
+				return nil
+			}
+
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+	}
+}
+
+// isEndOfResponse reports if a read error ends a response without delimiter and length,
+// a timeout ends it only if any bytes were received, otherwise it is a timeout error.
+func isEndOfResponse(err error, received bool) bool {
+	var netErr net.Error
+
+	return errors.Is(err, io.EOF) || received && errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// timings of tcp requests contain connect and ttfb, udp sockets are connected locally, so only ttfb is recorded.
+func (s *socketResult) timings(network string, startTime time.Time) tracking.Timings {
+	phases := make(map[string]time.Duration, len(tracking.Phases))
+
+	if network == networkTCP {
+		phases[tracking.PhaseConnect] = s.connected.Sub(startTime)
+	}
+
+	if !s.firstByte.IsZero() {
+		phases[tracking.PhaseTTFB] = s.firstByte.Sub(s.wrote)
+	}
+
+	return tracking.Timings{
+		Phases:     phases,
+		ConnOpened: network == networkTCP,
+	}
+}
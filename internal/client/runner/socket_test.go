@@ -0,0 +1,240 @@
+package runner
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lameaux/bro/internal/client/checker"
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/lameaux/bro/internal/client/thresholds"
+	"github.com/lameaux/bro/internal/client/tracking"
+)
+
+// startLineServer replies to every line with the same line in upper case.
+func startLineServer(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					_, _ = conn.Write([]byte(strings.ToUpper(scanner.Text()) + "\n"))
+				}
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+// startUDPServer replies to every datagram with the same datagram.
+func startUDPServer(t *testing.T) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	go func() {
+		buf := make([]byte, maxDatagramSize)
+
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+
+			_, _ = conn.WriteTo(buf[:n], addr)
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func newSocketRunner(t *testing.T, name, network string, conf *config.SocketRequest) (*Runner, *recordingListener) {
+	t.Helper()
+
+	scenario := &config.Scenario{
+		Name: name,
+		Checks: []*config.Check{
+			{Type: checker.TypeSocketResponse, Contains: "PING"},
+		},
+	}
+
+	if network == networkTCP {
+		scenario.TCPRequest = conf
+	} else {
+		scenario.UDPRequest = conf
+	}
+
+	listener := &recordingListener{}
+
+	r := New(nil, 0, scenario, thresholds.NewCheckCounters(), []StatListener{listener})
+	if err := r.prepare(context.Background()); err != nil {
+		t.Fatalf("failed to prepare: %v", err)
+	}
+
+	return r, listener
+}
+
+func TestProcessSocketMessage_TCP(t *testing.T) {
+	address := startLineServer(t)
+
+	r, listener := newSocketRunner(t, "tcp", networkTCP, &config.SocketRequest{
+		Address:  address,
+		Payload:  "ping ${id}\nignored\n",
+		Response: &config.SocketResponse{Delimiter: "\n"},
+	})
+	r.threadVars(0)["id"] = "7"
+
+	r.processMessage(context.Background(), 0, 1)
+
+	if len(listener.responses) != 1 || listener.successes != 1 {
+		t.Fatalf("expected successful response, got %d failed", len(listener.failed))
+	}
+
+	info := listener.responses[0]
+	if info.Code != socketCodeReceived || info.Method != "TCP" || info.URL != address {
+		t.Errorf("unexpected request info: %+v", info)
+	}
+
+	if info.Bytes.ReceivedBody != int64(len("PING 7\n")) {
+		t.Errorf("received %d bytes; expected only the first line", info.Bytes.ReceivedBody)
+	}
+
+	if _, ok := info.Timings.Phases[tracking.PhaseConnect]; !ok {
+		t.Errorf("connect timing is missing: %v", info.Timings.Phases)
+	}
+}
+
+func TestProcessSocketMessage_UDP(t *testing.T) {
+	address := startUDPServer(t)
+
+	r, listener := newSocketRunner(t, "udp", networkUDP, &config.SocketRequest{
+		Address:    address,
+		PayloadHex: "50 49 4e 47",
+		Response:   &config.SocketResponse{Length: 4},
+	})
+
+	r.processMessage(context.Background(), 0, 1)
+
+	if len(listener.responses) != 1 || listener.successes != 1 {
+		t.Fatalf("expected successful response, got %d failed", len(listener.failed))
+	}
+
+	if _, ok := listener.responses[0].Timings.Phases[tracking.PhaseConnect]; ok {
+		t.Errorf("unexpected connect timing for udp")
+	}
+}
+
+func TestProcessSocketMessage_Timeout(t *testing.T) {
+	address := startLineServer(t)
+
+	r, listener := newSocketRunner(t, "timeout", networkTCP, &config.SocketRequest{
+		Address:    address,
+		Payload:    "no newline",
+		Response:   &config.SocketResponse{Delimiter: "\n"},
+		TimeoutRaw: 100 * time.Millisecond,
+	})
+
+	r.processMessage(context.Background(), 0, 1)
+
+	if len(listener.failed) != 1 || listener.failed[0].ErrorCategory != tracking.ErrorTimeout {
+		t.Fatalf("expected timeout, got %d responses", len(listener.responses))
+	}
+}
+
+func TestProcessSocketMessage_UDPDatagram(t *testing.T) {
+	address := startUDPServer(t)
+
+	r, listener := newSocketRunner(t, "udp datagram", networkUDP, &config.SocketRequest{
+		Address:    address,
+		Payload:    "PING",
+		Response:   &config.SocketResponse{},
+		TimeoutRaw: time.Second,
+	})
+
+	r.processMessage(context.Background(), 0, 1)
+
+	if len(listener.responses) != 1 || listener.successes != 1 {
+		t.Fatalf("expected successful response, got %d failed", len(listener.failed))
+	}
+
+	if latency := listener.latencies[0]; latency >= time.Second {
+		t.Errorf("latency equals %s; expected response after the first datagram", latency)
+	}
+}
+
+func TestProcessSocketMessage_SilentServer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	// accepted connections are kept open without a reply
+	go func() {
+		var conns []net.Conn
+
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				for _, conn := range conns {
+					_ = conn.Close()
+				}
+
+				return
+			}
+
+			conns = append(conns, conn)
+		}
+	}()
+
+	r, recorder := newSocketRunner(t, "silent", networkTCP, &config.SocketRequest{
+		Address:    listener.Addr().String(),
+		Payload:    "ping",
+		Response:   &config.SocketResponse{},
+		TimeoutRaw: 100 * time.Millisecond,
+	})
+
+	r.processMessage(context.Background(), 0, 1)
+
+	if len(recorder.failed) != 1 || recorder.failed[0].ErrorCategory != tracking.ErrorTimeout {
+		t.Fatalf("expected timeout, got %d responses", len(recorder.responses))
+	}
+}
+
+func TestSocketExecutor_Prepare(t *testing.T) {
+	t.Parallel()
+
+	for _, conf := range []*config.SocketRequest{
+		{},
+		{Address: "localhost:1", PayloadHex: "zz"},
+	} {
+		executor := newSocketExecutor(networkTCP)(nil)
+
+		if _, err := executor.Prepare(context.Background(), &config.Scenario{TCPRequest: conf}); err == nil {
+			t.Errorf("expected error for %+v", conf)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/lameaux/bro/internal/client/checker"
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/lameaux/bro/internal/client/tracking"
+)
+
+const (
+	maxEventSize = 1 << 20
+	chunkSize    = 32 << 10
+)
+
+var errUnknownStreamFormat = errors.New("unknown stream format")
+
+// eventReader returns events of a streaming response until io.EOF.
+type eventReader interface {
+	next() (checker.Event, error)
+}
+
+// validateStreamFormat is called when scenario is prepared, newEventReader fails on unknown formats as well.
+func validateStreamFormat(format string) error {
+	switch format {
+	case config.StreamFormatSSE, config.StreamFormatLines, config.StreamFormatChunks:
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", errUnknownStreamFormat, format)
+	}
+}
+
+func newEventReader(format string, body io.Reader) (eventReader, error) {
+	switch format {
+	case config.StreamFormatSSE:
+		return &sseReader{scanner: newLineScanner(body)}, nil
+	case config.StreamFormatLines:
+		return &lineReader{scanner: newLineScanner(body)}, nil
+	case config.StreamFormatChunks:
+		return &chunkReader{body: body, buf: make([]byte, chunkSize)}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnknownStreamFormat, format)
+	}
+}
+
+func newLineScanner(body io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, chunkSize), maxEventSize)
+
+	return scanner
+}
+
+// sseReader parses text/event-stream, an event is dispatched on a blank line.
+// Comments, id and retry fields are skipped.
+type sseReader struct {
+	scanner *bufio.Scanner
+}
+
+func (r *sseReader) next() (checker.Event, error) {
+	var (
+		event   checker.Event
+		data    bytes.Buffer
+		hasData bool
+	)
+
+	for r.scanner.Scan() {
+		line := r.scanner.Bytes()
+
+		if len(line) == 0 {
+			if hasData {
+				event.Data = data.Bytes()
+
+				return event, nil
+			}
+
+			event.Type = ""
+
+			continue
+		}
+
+		field, value, _ := bytes.Cut(line, []byte(":"))
+		value = bytes.TrimPrefix(value, []byte(" "))
+
+		switch string(field) {
+		case "event":
+			event.Type = string(value)
+		case "data":
+			if hasData {
+				data.WriteByte('\n')
+			}
+
+			data.Write(value)
+
+			hasData = true
+		}
+	}
+
+	return scanEnd(r.scanner)
+}
+
+// lineReader treats every non-empty line as an event, e.g. newline delimited JSON.
+type lineReader struct {
+	scanner *bufio.Scanner
+}
+
+func (r *lineReader) next() (checker.Event, error) {
+	for r.scanner.Scan() {
+		if line := r.scanner.Bytes(); len(line) > 0 {
+			return checker.Event{Data: bytes.Clone(line)}, nil
+		}
+	}
+
+	return scanEnd(r.scanner)
+}
+
+// scanEnd returns a scanner error, an incomplete event at the end of the stream is discarded.
+func scanEnd(scanner *bufio.Scanner) (checker.Event, error) {
+	if err := scanner.Err(); err != nil {
+		return checker.Event{}, fmt.Errorf("%w: %w", tracking.ErrBodyRead, err)
+	}
+
+	return checker.Event{}, io.EOF
+}
+
+// chunkReader treats every read from the body as an event.
+type chunkReader struct {
+	body io.Reader
+	buf  []byte
+}
+
+func (r *chunkReader) next() (checker.Event, error) {
+	for {
+		n, err := r.body.Read(r.buf)
+		if n > 0 {
+			return checker.Event{Data: bytes.Clone(r.buf[:n])}, nil
+		}
+
+		if err != nil {
+			return checker.Event{}, err //nolint:wrapcheck
+		}
+	}
+}
+
+// streamResult is a summary of events read from a streaming response.
+type streamResult struct {
+	info         tracking.StreamInfo
+	firstEventAt time.Time
+	checks       []*config.Check
+	checkResults []checker.Result
+	success      bool
+}
+
+// readStream reads events until the end of response body and runs stream checks on each of them.
+func readStream(conf *config.Stream, body io.Reader) (*streamResult, error) {
+	events, err := newEventReader(conf.Format(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	streamChecker := checker.NewStreamChecker(conf.Checks)
+
+	result := &streamResult{checks: conf.Checks}
+
+	var lastEventAt time.Time
+
+	for {
+		event, err := events.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		now := time.Now()
+
+		if lastEventAt.IsZero() {
+			result.firstEventAt = now
+		} else {
+			result.info.Gaps = append(result.info.Gaps, now.Sub(lastEventAt))
+		}
+
+		lastEventAt = now
+		result.info.Events++
+
+		streamChecker.Validate(event)
+	}
+
+	result.checkResults, result.success = streamChecker.Results()
+
+	return result, nil
+}
+
+// addTimings adds time to the first event, measured from the moment request was written, and stream duration.
+func (s *streamResult) addTimings(timings tracking.Timings, trace *requestTrace, bodyDone time.Time) {
+	trace.mu.Lock()
+	defer trace.mu.Unlock()
+
+	if !s.firstEventAt.IsZero() && !trace.wroteRequest.IsZero() {
+		timings.Phases[tracking.PhaseFirstEvent] = s.firstEventAt.Sub(trace.wroteRequest)
+	}
+
+	if !trace.firstByte.IsZero() && !bodyDone.IsZero() {
+		timings.Phases[tracking.PhaseStream] = bodyDone.Sub(trace.firstByte)
+	}
+}
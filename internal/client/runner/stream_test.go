@@ -0,0 +1,197 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lameaux/bro/internal/client/checker"
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/lameaux/bro/internal/client/thresholds"
+	"github.com/lameaux/bro/internal/client/tracking"
+	"github.com/lameaux/bro/internal/shared/httpclient"
+)
+
+func readEvents(t *testing.T, format, body string) []checker.Event {
+	t.Helper()
+
+	events, err := newEventReader(format, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to create reader: %v", err)
+	}
+
+	var result []checker.Event
+
+	for {
+		event, err := events.next()
+		if err == io.EOF { //nolint:errorlint
+			return result
+		}
+
+		if err != nil {
+			t.Fatalf("failed to read event: %v", err)
+		}
+
+		result = append(result, event)
+	}
+}
+
+func TestSSEReader(t *testing.T) {
+	t.Parallel()
+
+	body := ": keep-alive\n\n" +
+		"data: first\n\n" +
+		"event: update\r\nid: 2\r\ndata: line 1\r\ndata: line 2\r\n\r\n" +
+		"data: incomplete"
+
+	events := readEvents(t, config.StreamFormatSSE, body)
+	if len(events) != 2 {
+		t.Fatalf("got %d events; expected 2", len(events))
+	}
+
+	if events[0].Type != "" || string(events[0].Data) != "first" {
+		t.Errorf("unexpected first event: %q %q", events[0].Type, events[0].Data)
+	}
+
+	if events[1].Type != "update" || string(events[1].Data) != "line 1\nline 2" {
+		t.Errorf("unexpected second event: %q %q", events[1].Type, events[1].Data)
+	}
+}
+
+func TestLineReader(t *testing.T) {
+	t.Parallel()
+
+	events := readEvents(t, config.StreamFormatLines, "{\"n\": 1}\n\n{\"n\": 2}\n{\"n\": 3}")
+	if len(events) != 3 || string(events[2].Data) != `{"n": 3}` {
+		t.Errorf("unexpected events: %v", events)
+	}
+}
+
+func TestNewEventReader_UnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	if _, err := newEventReader("xml", strings.NewReader("")); err == nil {
+		t.Errorf("expected error for unknown format")
+	}
+}
+
+func TestPrepare_UnknownStreamFormat(t *testing.T) {
+	t.Parallel()
+
+	scenario := &config.Scenario{
+		Name: "stream",
+		HTTPRequest: config.HTTPRequest{
+			URL:    "http://localhost",
+			Stream: &config.Stream{FormatRaw: "xml"},
+		},
+	}
+
+	_, err := newHTTPExecutor(nil).Prepare(context.Background(), scenario)
+	if !errors.Is(err, errUnknownStreamFormat) {
+		t.Errorf("expected unknown stream format, got %v", err)
+	}
+}
+
+func startStreamServer(t *testing.T) string {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+
+		for i := 1; i <= 3; i++ {
+			time.Sleep(50 * time.Millisecond)
+
+			_, _ = fmt.Fprintf(w, "data: {\"n\": %d}\n\n", i)
+			w.(http.Flusher).Flush()
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server.URL
+}
+
+func newStreamRunner(t *testing.T, url string, checks []*config.Check) (*Runner, *recordingListener) {
+	t.Helper()
+
+	clients, err := httpclient.NewClients(config.HTTPClient{})
+	if err != nil {
+		t.Fatalf("failed to create clients: %v", err)
+	}
+
+	scenario := &config.Scenario{
+		Name: "stream",
+		HTTPRequest: config.HTTPRequest{
+			URL: url,
+			Stream: &config.Stream{
+				Checks: []*config.Check{
+					{Type: checker.TypeEvent, Name: "n", Equals: "3"},
+				},
+			},
+		},
+		Checks: checks,
+	}
+
+	listener := &recordingListener{}
+
+	r := New(clients, 0, scenario, thresholds.NewCheckCounters(), []StatListener{listener})
+	if err = r.prepare(context.Background()); err != nil {
+		t.Fatalf("failed to prepare: %v", err)
+	}
+
+	return r, listener
+}
+
+func checkStreamTimings(t *testing.T, info *tracking.RequestInfo) {
+	t.Helper()
+
+	if info.Stream == nil || info.Stream.Events != 3 || len(info.Stream.Gaps) != 2 {
+		t.Fatalf("unexpected stream: %+v", info.Stream)
+	}
+
+	for _, gap := range info.Stream.Gaps {
+		if gap < 40*time.Millisecond {
+			t.Errorf("gap equals %v; expected about 50ms", gap)
+		}
+	}
+
+	phases := info.Timings.Phases
+	if phases[tracking.PhaseFirstEvent] < 40*time.Millisecond || phases[tracking.PhaseStream] < 90*time.Millisecond {
+		t.Errorf("unexpected timings: %v", phases)
+	}
+}
+
+func TestProcessMessage_Stream(t *testing.T) {
+	r, listener := newStreamRunner(t, startStreamServer(t), nil)
+
+	r.processMessage(context.Background(), 0, 1)
+
+	if len(listener.responses) != 1 {
+		t.Fatalf("got %d responses; expected 1", len(listener.responses))
+	}
+
+	if listener.successes != 1 {
+		t.Errorf("expected stream checks to pass")
+	}
+
+	checkStreamTimings(t, listener.responses[0])
+}
+
+func TestProcessMessage_StreamBodyCheck(t *testing.T) {
+	r, listener := newStreamRunner(t, startStreamServer(t), []*config.Check{
+		{Type: checker.TypeHTTPBody, Contains: `{"n": 3}`},
+	})
+
+	r.processMessage(context.Background(), 0, 1)
+
+	if len(listener.responses) != 1 || listener.successes != 1 {
+		t.Fatalf("expected stream and body checks to pass, got %d failed", len(listener.failed))
+	}
+
+	checkStreamTimings(t, listener.responses[0])
+}
@@ -0,0 +1,173 @@
+package runner
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/lameaux/bro/internal/client/tracking"
+)
+
+type requestTrace struct {
+	mu sync.Mutex
+
+	dnsStart, dnsDone         time.Time
+	connectStart, connectDone time.Time
+	tlsStart, tlsDone         time.Time
+	wroteRequest, firstByte   time.Time
+
+	connOpened   bool
+	connReused   bool
+	tlsHandshake bool
+	tlsResumed   bool
+	headerBytes  int64
+}
+
+func (t *requestTrace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(_ httptrace.DNSStartInfo) { t.set(&t.dnsStart) },
+		DNSDone:  func(_ httptrace.DNSDoneInfo) { t.set(&t.dnsDone) },
+		ConnectStart: func(_, _ string) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+
+			// several addresses may be dialed in parallel, keep the first one
+			if t.connectStart.IsZero() {
+				t.connectStart = time.Now()
+			}
+		},
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				t.set(&t.connectDone)
+			}
+		},
+		TLSHandshakeStart: func() { t.set(&t.tlsStart) },
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			t.set(&t.tlsDone)
+
+			if err == nil {
+				t.mu.Lock()
+				defer t.mu.Unlock()
+
+				t.tlsHandshake = true
+				t.tlsResumed = state.DidResume
+			}
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+
+			t.connOpened = !info.Reused
+			t.connReused = info.Reused
+		},
+		WroteHeaderField: func(key string, values []string) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+
+			for _, value := range values {
+				t.headerBytes += int64(len(key) + len(": ") + len(value) + len("\r\n"))
+			}
+		},
+		WroteRequest:         func(_ httptrace.WroteRequestInfo) { t.set(&t.wroteRequest) },
+		GotFirstResponseByte: func() { t.set(&t.firstByte) },
+	}
+}
+
+// webSocketTrace treats upgrade request as written once connection is ready, websocket dialer does not report it.
+func (t *requestTrace) webSocketTrace() *httptrace.ClientTrace {
+	trace := t.clientTrace()
+
+	gotConn := trace.GotConn
+	trace.GotConn = func(info httptrace.GotConnInfo) {
+		gotConn(info)
+		t.set(&t.wroteRequest)
+	}
+
+	tlsHandshakeDone := trace.TLSHandshakeDone
+	trace.TLSHandshakeDone = func(state tls.ConnectionState, err error) {
+		tlsHandshakeDone(state, err)
+		t.set(&t.wroteRequest)
+	}
+
+	return trace
+}
+
+func (t *requestTrace) set(field *time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	*field = time.Now()
+}
+
+// timings calculates request phases, ttfb is measured from the moment request was written.
+func (t *requestTrace) timings(bodyDone time.Time) tracking.Timings {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	phases := make(map[string]time.Duration, len(tracking.Phases))
+
+	addPhase := func(phase string, start, end time.Time) {
+		if !start.IsZero() && !end.IsZero() {
+			phases[phase] = end.Sub(start)
+		}
+	}
+
+	addPhase(tracking.PhaseDNS, t.dnsStart, t.dnsDone)
+	addPhase(tracking.PhaseConnect, t.connectStart, t.connectDone)
+	addPhase(tracking.PhaseTLS, t.tlsStart, t.tlsDone)
+	addPhase(tracking.PhaseTTFB, t.wroteRequest, t.firstByte)
+	addPhase(tracking.PhaseDownload, t.firstByte, bodyDone)
+
+	return tracking.Timings{
+		Phases:       phases,
+		ConnOpened:   t.connOpened,
+		ConnReused:   t.connReused,
+		TLSHandshake: t.tlsHandshake,
+		TLSResumed:   t.tlsResumed,
+	}
+}
+
+// sentHeaderBytes estimates size of request line and headers written by transport.
+func (t *requestTrace) sentHeaderBytes(req *http.Request) int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	requestLine := len(req.Method) + len(" ") + len(req.URL.RequestURI()) + len(" ") + len(req.Proto) + len("\r\n")
+
+	return int64(requestLine) + t.headerBytes + int64(len("\r\n"))
+}
+
+// timedBody remembers when response body was fully read.
+type timedBody struct {
+	io.ReadCloser
+
+	doneAt time.Time
+	err    error
+}
+
+func (b *timedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && b.doneAt.IsZero() {
+		b.doneAt = time.Now()
+
+		if !errors.Is(err, io.EOF) {
+			b.err = fmt.Errorf("%w: %w", tracking.ErrBodyRead, err)
+		}
+	}
+
+	return n, err //nolint:wrapcheck
+}
+
+// drain reads the rest of the body and returns the moment it was fully read.
+func (b *timedBody) drain() (time.Time, error) {
+	if b.doneAt.IsZero() {
+		_, _ = io.Copy(io.Discard, b)
+	}
+
+	return b.doneAt, b.err
+}
@@ -1,34 +1,132 @@
 package runner
 
 import (
-	"net/http"
-	"strconv"
+	"context"
 	"time"
 
+	"github.com/lameaux/bro/internal/client/checker"
+	"github.com/lameaux/bro/internal/client/config"
 	"github.com/lameaux/bro/internal/client/tracking"
 )
 
-func (r *Runner) trackError(err error) {
+// Tracker reports responses of a session to stat listeners of the runner,
+// a session may report several requests, e.g. a connection and its messages.
+type Tracker struct {
+	r   *Runner
+	ctx context.Context //nolint:containedctx
+}
+
+// Request is called before a request is sent.
+func (t *Tracker) Request() {
+	t.r.trackRequest(t.ctx)
+}
+
+// Response reports a received response, success is false when its checks failed.
+func (t *Tracker) Response(resp *Response, success bool) {
+	t.r.trackResponse(t.ctx, resp, success)
+}
+
+// Failed reports a failed request, resp may be nil or set its code and method.
+func (t *Tracker) Failed(resp *Response, err error) {
+	t.r.trackError(t.ctx, resp, err)
+}
+
+// Checks counts results of checks which are not scenario checks for thresholds.
+func (t *Tracker) Checks(checks []*config.Check, results []checker.Result) {
+	t.r.checks.Update(checks, results)
+}
+
+// LogChecks logs check results of a response at debug level.
+func (t *Tracker) LogChecks(resp *Response, checks []*config.Check, results []checker.Result, success bool) {
+	t.r.logCheckResults(t.ctx, resp, checks, results, success)
+}
+
+// Connection reports a closed connection, its scenario and stage are set by tracker.
+func (t *Tracker) Connection(info *tracking.ConnectionInfo) {
+	info.Scenario = t.r.scenario.Name
+	info.Stage, _ = t.ctx.Value(contextKey("stage")).(string)
+
+	for _, l := range t.r.listeners {
+		if connectionListener, ok := l.(ConnectionListener); ok {
+			connectionListener.TrackConnection(info)
+		}
+	}
+}
+
+func (r *Runner) trackStage(stage string, startRPS, targetRPS, threads int, duration time.Duration) {
+	info := &tracking.StageInfo{
+		Scenario:  r.scenario.Name,
+		Stage:     stage,
+		StartRPS:  startRPS,
+		TargetRPS: targetRPS,
+		Threads:   threads,
+		Duration:  duration,
+	}
+
+	for _, l := range r.listeners {
+		if stageListener, ok := l.(StageListener); ok {
+			stageListener.TrackStage(info)
+		}
+	}
+}
+
+func (r *Runner) trackRequest(ctx context.Context) {
+	var info *tracking.RequestInfo
+
+	for _, l := range r.listeners {
+		if requestListener, ok := l.(RequestListener); ok {
+			if info == nil {
+				info = r.requestInfo(ctx, nil)
+			}
+
+			requestListener.TrackRequest(info)
+		}
+	}
+}
+
+func (r *Runner) trackError(ctx context.Context, resp *Response, err error) {
+	info := r.requestInfo(ctx, resp)
+	info.ErrorCategory = tracking.ClassifyError(err)
+
 	for _, l := range r.listeners {
-		l.TrackFailed(r.requestInfo(nil), err)
+		l.TrackFailed(info, err)
 	}
 }
 
-func (r *Runner) trackResponse(resp *http.Response, success bool, latency time.Duration) {
+func (r *Runner) trackResponse(ctx context.Context, resp *Response, success bool) {
+	info := r.requestInfo(ctx, resp)
+	info.Timings = resp.Timings
+	info.Bytes = resp.Bytes
+	info.Stream = resp.Stream
+	info.Metrics = resp.Metrics
+
 	for _, l := range r.listeners {
-		l.TrackResponse(r.requestInfo(resp), success, latency)
+		l.TrackResponse(info, success, resp.Latency)
 	}
 }
 
-func (r *Runner) requestInfo(resp *http.Response) *tracking.RequestInfo {
+// requestInfo describes a request with scenario labels, response sets its code and protocol.
+func (r *Runner) requestInfo(ctx context.Context, resp *Response) *tracking.RequestInfo {
+	stage, _ := ctx.Value(contextKey("stage")).(string)
+
 	info := &tracking.RequestInfo{
 		Scenario: r.scenario.Name,
-		Method:   r.scenario.HTTPRequest.Method(),
-		URL:      r.scenario.HTTPRequest.URL,
+		Method:   r.labels.Method,
+		URL:      r.labels.URL,
+		Stage:    stage,
+		Protocol: r.labels.Protocol,
 	}
 
 	if resp != nil {
-		info.Code = strconv.Itoa(resp.StatusCode)
+		info.Code = resp.Code
+
+		if resp.Method != "" {
+			info.Method = resp.Method
+		}
+
+		if resp.Protocol != "" {
+			info.Protocol = resp.Protocol
+		}
 	}
 
 	return info
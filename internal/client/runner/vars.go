@@ -0,0 +1,9 @@
+package runner
+
+// threadVars returns variables extracted from previous responses of the thread.
+// Each map is accessed by its thread only.
+func (r *Runner) threadVars(threadID int) map[string]string {
+	vars, _ := r.vars.LoadOrStore(threadID, make(map[string]string))
+
+	return vars.(map[string]string) //nolint:forcetypeassert
+}
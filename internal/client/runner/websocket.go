@@ -0,0 +1,363 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/lameaux/bro/internal/client/checker"
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/lameaux/bro/internal/client/tracking"
+	"github.com/lameaux/bro/internal/shared/httpclient"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	protocolWebSocket = "websocket"
+
+	wsMethodMessage = "MESSAGE"
+	wsCodeMessage   = "message"
+)
+
+var (
+	errInvalidWebSocketURL = errors.New("url must have ws or wss scheme")
+	errWebSocketChecks     = errors.New("scenario checks are not supported, use checks of websocket messages")
+	errWebSocketStages     = errors.New("stages are not supported with held connections")
+)
+
+// webSocketExecutor opens a connection for every session and runs a script of messages,
+// the connection and every message are tracked as responses.
+type webSocketExecutor struct {
+	conf   *config.WebSocket
+	dialer *websocket.Dialer
+}
+
+func newWebSocketExecutor(_ HTTPClients) Executor { //nolint:ireturn
+	return &webSocketExecutor{}
+}
+
+func (e *webSocketExecutor) Prepare(_ context.Context, scenario *config.Scenario) (Labels, error) {
+	if scenario.WebSocket == nil {
+		return Labels{}, errMissingRequest
+	}
+
+	// replies are validated by checks of messages, held connections are opened at constant rate
+	if len(scenario.Checks) > 0 {
+		return Labels{}, errWebSocketChecks
+	}
+
+	if len(scenario.Stages) > 0 && scenario.WebSocket.Connections > 0 {
+		return Labels{}, errWebSocketStages
+	}
+
+	dialer, err := newWebSocketDialer(scenario.WebSocket)
+	if err != nil {
+		return Labels{}, err
+	}
+
+	e.conf = scenario.WebSocket
+	e.dialer = dialer
+
+	return Labels{
+		Method:   http.MethodGet,
+		URL:      scenario.WebSocket.URL,
+		Protocol: protocolWebSocket,
+	}, nil
+}
+
+func (e *webSocketExecutor) Held() int {
+	return e.conf.Connections
+}
+
+func newWebSocketDialer(conf *config.WebSocket) (*websocket.Dialer, error) {
+	u, err := url.Parse(conf.URL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidWebSocketURL, err)
+	}
+
+	if u.Scheme != "ws" && u.Scheme != "wss" {
+		return nil, errInvalidWebSocketURL
+	}
+
+	dialer := &websocket.Dialer{
+		HandshakeTimeout: conf.Timeout(),
+		Subprotocols:     conf.Subprotocols,
+	}
+
+	if conf.TLS != nil {
+		tlsConfig, err := httpclient.NewTLSConfig(*conf.TLS)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tls: %w", err)
+		}
+
+		dialer.TLSClientConfig = tlsConfig
+	}
+
+	return dialer, nil
+}
+
+// Session opens a connection and runs messages once, or until context is done for held connections.
+func (e *webSocketExecutor) Session(ctx context.Context, req *Request, tracker *Tracker) {
+	conn, ok := e.open(ctx, req, tracker)
+	if !ok {
+		return
+	}
+
+	openedAt := time.Now()
+
+	messages, err := e.runMessages(ctx, conn, tracker, req.Held)
+
+	closeCode, normal := e.close(conn, err)
+
+	tracker.Connection(&tracking.ConnectionInfo{
+		URL:       e.conf.URL,
+		CloseCode: closeCode,
+		Normal:    normal,
+		Lifetime:  time.Since(openedAt),
+		Messages:  messages,
+	})
+}
+
+func (e *webSocketExecutor) open(ctx context.Context, req *Request, tracker *Tracker) (*websocket.Conn, bool) {
+	conf := e.conf
+
+	tracker.Request()
+
+	header := make(http.Header, len(conf.Headers))
+	for key, value := range conf.Headers {
+		header.Set(key, value)
+	}
+
+	trace := &requestTrace{}
+
+	startTime := time.Now()
+
+	conn, resp, err := e.dialer.DialContext(httptrace.WithClientTrace(ctx, trace.webSocketTrace()), conf.URL, header)
+
+	latency := time.Since(startTime)
+
+	var response *Response
+
+	if resp != nil {
+		defer resp.Body.Close()
+
+		response = &Response{
+			Code:    strconv.Itoa(resp.StatusCode),
+			Latency: latency,
+			Timings: trace.timings(time.Time{}),
+			Bytes: tracking.Bytes{
+				ReceivedHeaders: responseHeaderSize(resp),
+			},
+		}
+	}
+
+	if err != nil {
+		log.Debug().
+			Int("threadID", req.ThreadID).
+			Int("msgID", req.MsgID).
+			Err(err).
+			Msg("failed to open websocket")
+
+		tracker.Failed(response, err)
+
+		return nil, false
+	}
+
+	tracker.Response(response, true)
+
+	return conn, true
+}
+
+func (e *webSocketExecutor) runMessages(
+	ctx context.Context,
+	conn *websocket.Conn,
+	tracker *Tracker,
+	repeat bool,
+) (int64, error) {
+	var messages int64
+
+	if len(e.conf.Messages) == 0 {
+		if repeat {
+			return messages, holdWebSocket(ctx, conn)
+		}
+
+		return messages, nil
+	}
+
+	for {
+		for _, msg := range e.conf.Messages {
+			if msg.Pause > 0 {
+				select {
+				case <-ctx.Done():
+					return messages, nil
+				case <-time.After(msg.Pause):
+				}
+			}
+
+			// a started step is completed even when scenario ends, its duration is bounded by timeout
+			if ctx.Err() != nil {
+				return messages, nil
+			}
+
+			count, err := e.runMessage(conn, tracker, msg)
+			messages += count
+
+			if err != nil {
+				return messages, err
+			}
+		}
+
+		if !repeat || ctx.Err() != nil {
+			return messages, nil
+		}
+	}
+}
+
+// holdWebSocket reads and discards messages until context is done or connection is closed by server.
+func holdWebSocket(ctx context.Context, conn *websocket.Conn) error {
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.SetReadDeadline(time.Now())
+	})
+	defer stop()
+
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return err //nolint:wrapcheck
+		}
+	}
+}
+
+// runMessage sends a message and waits for a reply passing checks, other messages are skipped.
+func (e *webSocketExecutor) runMessage(
+	conn *websocket.Conn,
+	tracker *Tracker,
+	msg *config.WebSocketMessage,
+) (int64, error) {
+	tracker.Request()
+
+	var (
+		messages int64
+		bytes    tracking.Bytes
+	)
+
+	timeout := msg.Timeout
+	if timeout == 0 {
+		timeout = e.conf.Timeout()
+	}
+
+	startTime := time.Now()
+
+	if msg.Send != "" {
+		messageType := websocket.TextMessage
+		if msg.Binary {
+			messageType = websocket.BinaryMessage
+		}
+
+		_ = conn.SetWriteDeadline(startTime.Add(timeout))
+
+		if err := conn.WriteMessage(messageType, []byte(msg.Send)); err != nil {
+			tracker.Failed(&Response{Method: wsMethodMessage}, err)
+
+			return messages, fmt.Errorf("failed to send message: %w", err)
+		}
+
+		messages++
+		bytes.SentBody = int64(len(msg.Send))
+	}
+
+	if !msg.ExpectsReply() {
+		tracker.Response(messageResponse(time.Since(startTime), bytes), true)
+
+		return messages, nil
+	}
+
+	_ = conn.SetReadDeadline(startTime.Add(timeout))
+
+	responseChecker := checker.New(msg.Checks)
+	checkResults := make([]checker.Result, len(msg.Checks))
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			tracker.Checks(msg.Checks, checkResults)
+			tracker.Failed(&Response{Method: wsMethodMessage}, err)
+
+			return messages, fmt.Errorf("failed to receive message: %w", err)
+		}
+
+		messages++
+		bytes.ReceivedBody += int64(len(data))
+		bytes.ReceivedBodyDecoded += int64(len(data))
+
+		var success bool
+
+		checkResults, success = responseChecker.ValidateWebSocket(data)
+		if !success {
+			continue
+		}
+
+		response := messageResponse(time.Since(startTime), bytes)
+
+		tracker.LogChecks(response, msg.Checks, checkResults, true)
+		tracker.Response(response, true)
+		tracker.Checks(msg.Checks, checkResults)
+
+		return messages, nil
+	}
+}
+
+// close sends a close frame unless connection was closed by error and returns close code.
+func (e *webSocketExecutor) close(conn *websocket.Conn, err error) (int, bool) {
+	defer conn.Close()
+
+	var closeErr *websocket.CloseError
+	if errors.As(err, &closeErr) {
+		return closeErr.Code, isNormalClose(closeErr.Code)
+	}
+
+	if err != nil {
+		return websocket.CloseAbnormalClosure, false
+	}
+
+	deadline := time.Now().Add(e.conf.Timeout())
+
+	msg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	if err = conn.WriteControl(websocket.CloseMessage, msg, deadline); err != nil {
+		return websocket.CloseAbnormalClosure, false
+	}
+
+	// wait for server to confirm
+	_ = conn.SetReadDeadline(deadline)
+
+	for {
+		if _, _, err = conn.ReadMessage(); err != nil {
+			break
+		}
+	}
+
+	return websocket.CloseNormalClosure, true
+}
+
+func isNormalClose(code int) bool {
+	return code == websocket.CloseNormalClosure || code == websocket.CloseGoingAway
+}
+
+// messageResponse is a reply or a sent message which does not wait for a reply.
+func messageResponse(latency time.Duration, bytes tracking.Bytes) *Response {
+	return &Response{
+		Method:  wsMethodMessage,
+		Code:    wsCodeMessage,
+		Latency: latency,
+		Bytes:   bytes,
+	}
+}
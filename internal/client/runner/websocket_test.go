@@ -0,0 +1,266 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/lameaux/bro/internal/client/checker"
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/lameaux/bro/internal/client/thresholds"
+	"github.com/lameaux/bro/internal/client/tracking"
+)
+
+type recordingListener struct {
+	mu          sync.Mutex
+	responses   []*tracking.RequestInfo
+	latencies   []time.Duration
+	successes   int
+	failed      []*tracking.RequestInfo
+	connections []*tracking.ConnectionInfo
+}
+
+func (l *recordingListener) TrackFailed(info *tracking.RequestInfo, _ error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.failed = append(l.failed, info)
+}
+
+func (l *recordingListener) TrackResponse(info *tracking.RequestInfo, success bool, latency time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.responses = append(l.responses, info)
+	l.latencies = append(l.latencies, latency)
+
+	if success {
+		l.successes++
+	}
+}
+
+func (l *recordingListener) TrackConnection(info *tracking.ConnectionInfo) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.connections = append(l.connections, info)
+}
+
+func (l *recordingListener) codes() map[string]int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	codes := make(map[string]int)
+	for _, info := range l.responses {
+		codes[info.Code]++
+	}
+
+	return codes
+}
+
+// startEchoServer greets every connection with a notice and replies to text messages with {"echo": message}.
+func startEchoServer(t *testing.T) string {
+	t.Helper()
+
+	upgrader := websocket.Upgrader{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		_ = conn.WriteMessage(websocket.TextMessage, []byte("notice"))
+
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			reply, _ := json.Marshal(map[string]string{"echo": string(data)})
+			if err = conn.WriteMessage(websocket.TextMessage, reply); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func newWebSocketRunner(t *testing.T, name string, conf *config.WebSocket) (*Runner, *recordingListener) {
+	t.Helper()
+
+	listener := &recordingListener{}
+
+	r := New(nil, 0, &config.Scenario{Name: name, WebSocket: conf}, thresholds.NewCheckCounters(), []StatListener{listener})
+	if err := r.prepare(context.Background()); err != nil {
+		t.Fatalf("failed to prepare: %v", err)
+	}
+
+	return r, listener
+}
+
+func TestRunWebSocket(t *testing.T) {
+	url := startEchoServer(t)
+
+	r, listener := newWebSocketRunner(t, "websocket", &config.WebSocket{
+		URL: url,
+		Messages: []*config.WebSocketMessage{
+			{
+				Send: "hello",
+				Checks: []*config.Check{
+					{Type: checker.TypeWSMessage, Name: "echo", Equals: "hello"},
+				},
+			},
+			{
+				Send: "fire and forget",
+			},
+			{
+				Send:    "bye",
+				Timeout: 100 * time.Millisecond,
+				Checks: []*config.Check{
+					{Type: checker.TypeWSMessage, Contains: "never"},
+				},
+			},
+		},
+	})
+
+	r.processMessage(context.Background(), 0, 0)
+
+	codes := listener.codes()
+	if codes["101"] != 1 || codes[wsCodeMessage] != 2 {
+		t.Errorf("unexpected responses: %v", codes)
+	}
+
+	if len(listener.failed) != 1 || listener.failed[0].ErrorCategory != tracking.ErrorTimeout {
+		t.Fatalf("expected reply timeout, got %v", listener.failed)
+	}
+
+	if listener.failed[0].Method != wsMethodMessage {
+		t.Errorf("method equals %s; expected %s", listener.failed[0].Method, wsMethodMessage)
+	}
+
+	if len(listener.connections) != 1 {
+		t.Fatalf("expected closed connection, got %d", len(listener.connections))
+	}
+
+	closed := listener.connections[0]
+	if closed.Normal || closed.CloseCode != websocket.CloseAbnormalClosure {
+		t.Errorf("close code equals %d; expected abnormal closure", closed.CloseCode)
+	}
+
+	// notice, hello, echo, fire and forget, echo, bye and echo of bye
+	if closed.Messages != 7 {
+		t.Errorf("messages equal %d; expected 7", closed.Messages)
+	}
+}
+
+func TestRunHeldConnections(t *testing.T) {
+	url := startEchoServer(t)
+
+	r, listener := newWebSocketRunner(t, "held", &config.WebSocket{
+		URL:         url,
+		Connections: 2,
+		Messages: []*config.WebSocketMessage{
+			{
+				Pause: 100 * time.Millisecond,
+				Send:  "ping",
+				Checks: []*config.Check{
+					{Type: checker.TypeWSMessage, Name: "echo", Equals: "ping"},
+				},
+			},
+		},
+	})
+	r.scenario.RpsRaw = 10
+
+	session, _ := r.executor.(SessionExecutor)
+
+	if err := r.runHeldSessions(context.Background(), session); err != nil {
+		t.Fatalf("failed to run: %v", err)
+	}
+
+	codes := listener.codes()
+	if codes["101"] != 2 {
+		t.Errorf("opened %d connections; expected 2", codes["101"])
+	}
+
+	if codes[wsCodeMessage] < 10 {
+		t.Errorf("got %d replies; expected at least 10", codes[wsCodeMessage])
+	}
+
+	if len(listener.failed) != 0 {
+		t.Errorf("unexpected failures: %d", len(listener.failed))
+	}
+
+	for _, closed := range listener.connections {
+		if !closed.Normal || closed.Lifetime < 500*time.Millisecond {
+			t.Errorf("unexpected connection close: %+v", closed)
+		}
+	}
+}
+
+func TestPrepareWebSocket(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		scenario *config.Scenario
+		err      error
+	}{
+		{
+			name: "checks",
+			scenario: &config.Scenario{
+				WebSocket: &config.WebSocket{URL: "ws://localhost"},
+				Checks:    []*config.Check{{Type: checker.TypeHTTPCode, Equals: "101"}},
+			},
+			err: errWebSocketChecks,
+		},
+		{
+			name: "stages of held connections",
+			scenario: &config.Scenario{
+				WebSocket: &config.WebSocket{URL: "ws://localhost", Connections: 10},
+				Stages:    []*config.Stage{{}},
+			},
+			err: errWebSocketStages,
+		},
+		{
+			name: "stages of connections per request",
+			scenario: &config.Scenario{
+				WebSocket: &config.WebSocket{URL: "ws://localhost"},
+				Stages:    []*config.Stage{{}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := newWebSocketExecutor(nil).Prepare(context.Background(), tt.scenario); !errors.Is(err, tt.err) {
+				t.Errorf("got error %v; expected %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestNewWebSocketDialer(t *testing.T) {
+	t.Parallel()
+
+	if _, err := newWebSocketDialer(&config.WebSocket{URL: "http://localhost"}); err == nil {
+		t.Errorf("expected error for http url")
+	}
+
+	if _, err := newWebSocketDialer(&config.WebSocket{URL: "wss://localhost/chat"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
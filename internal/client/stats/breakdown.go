@@ -0,0 +1,106 @@
+package stats
+
+import (
+	"time"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+	"github.com/rs/zerolog/log"
+)
+
+const codeError = "error"
+
+// BreakdownStats is a snapshot of requests grouped by status code or stage.
+type BreakdownStats struct {
+	Name          string
+	Total         int64
+	Success       int64
+	Failed        int64
+	Codes         map[string]int64
+	LatencyMillis map[float64]int64
+}
+
+type breakdown struct {
+	total, success, failed int64
+
+	codes         map[string]int64
+	latencyMillis *hdrhistogram.Histogram
+}
+
+type breakdowns struct {
+	names   []string
+	entries map[string]*breakdown
+}
+
+func newBreakdowns() *breakdowns {
+	return &breakdowns{
+		entries: make(map[string]*breakdown),
+	}
+}
+
+func (b *breakdowns) get(name string) *breakdown {
+	entry, ok := b.entries[name]
+	if !ok {
+		entry = &breakdown{
+			codes:         make(map[string]int64),
+			latencyMillis: hdrhistogram.New(1, 1e6, 3), //nolint:mnd,gomnd
+		}
+		b.entries[name] = entry
+		b.names = append(b.names, name)
+	}
+
+	return entry
+}
+
+func (b *breakdowns) trackResponse(name, code string, success bool, latency time.Duration) {
+	entry := b.get(name)
+
+	entry.total++
+	entry.codes[code]++
+
+	if success {
+		entry.success++
+	} else {
+		entry.failed++
+	}
+
+	if err := entry.latencyMillis.RecordValue(latency.Milliseconds()); err != nil {
+		log.Warn().Err(err).Msg("failed to record latency")
+	}
+}
+
+func (b *breakdowns) trackFailed(name, code string) {
+	entry := b.get(name)
+
+	entry.total++
+	entry.failed++
+	entry.codes[code]++
+}
+
+func (b *breakdowns) snapshot(percentiles []float64) []BreakdownStats {
+	result := make([]BreakdownStats, 0, len(b.names))
+
+	for _, name := range b.names {
+		entry := b.entries[name]
+
+		codes := make(map[string]int64, len(entry.codes))
+		for code, count := range entry.codes {
+			codes[code] = count
+		}
+
+		latency := make(map[float64]int64, len(percentiles))
+		for _, percentile := range percentiles {
+			latency[percentile] = entry.latencyMillis.ValueAtPercentile(percentile)
+		}
+
+		result = append(result, BreakdownStats{
+			Name:          name,
+			Total:         entry.total,
+			Success:       entry.success,
+			Failed:        entry.failed,
+			Codes:         codes,
+			LatencyMillis: latency,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,33 @@
+package stats
+
+import (
+	"strconv"
+
+	"github.com/lameaux/bro/internal/client/tracking"
+	"github.com/rs/zerolog/log"
+)
+
+// TrackConnection counts closed websocket connections by close code and records their lifetime.
+func (c *Counters) TrackConnection(info *tracking.ConnectionInfo) {
+	c.incCounter(CounterClosed)
+	c.incCounter(counterClosePrefix + strconv.Itoa(info.CloseCode))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.lifetimeMillis.RecordValue(info.Lifetime.Milliseconds()); err != nil {
+		log.Warn().Err(err).Msg("failed to record connection lifetime")
+	}
+}
+
+// CloseCodes returns number of closed websocket connections per close code.
+func (c *Counters) CloseCodes() map[string]int64 {
+	return c.countersWithPrefix(counterClosePrefix)
+}
+
+func (c *Counters) LifetimeAtPercentile(percentile float64) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lifetimeMillis.ValueAtPercentile(percentile)
+}
@@ -1,8 +1,10 @@
 package stats
 
 import (
-	"errors"
-	"net"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -18,18 +20,60 @@ const (
 	CounterFailed  = "failed"
 	CounterTimeout = "timeout"
 	CounterInvalid = "invalid"
+	CounterReused  = "reused"
+
+	CounterConnections = "connections"
+	CounterClosed      = "closed"
+	CounterStreams     = "streams"
+	CounterEvents      = "events"
+
+	CounterTLSHandshakes = "tlsHandshakes"
+	CounterTLSResumed    = "tlsResumed"
+
+	CounterBytesSent            = "bytesSent"
+	CounterBytesReceived        = "bytesReceived"
+	CounterBytesReceivedDecoded = "bytesReceivedDecoded"
+
+	counterErrorPrefix    = "error."
+	counterProtocolPrefix = "protocol."
+	counterClosePrefix    = "close."
+	counterMetricPrefix   = "metric."
+
+	maxErrorSamples = 10
 )
 
+type ErrorSample struct {
+	Message string
+	Count   int64
+}
+
 type Counters struct {
 	m sync.Map
 
-	latencyMillis *hdrhistogram.Histogram
-	mu            sync.Mutex
+	latencyMillis  *hdrhistogram.Histogram
+	lifetimeMillis *hdrhistogram.Histogram
+	timingsMicros  map[string]*hdrhistogram.Histogram
+	errorSamples   map[string]*ErrorSample // by errorSampleKey
+	byCode         *breakdowns
+	byStage        *breakdowns
+	mu             sync.Mutex
 }
 
 func NewCounters() *Counters {
+	phases := append(slices.Clone(tracking.Phases), tracking.StreamPhases...)
+
+	timingsMicros := make(map[string]*hdrhistogram.Histogram, len(phases))
+	for _, phase := range phases {
+		timingsMicros[phase] = hdrhistogram.New(1, 1e9, 3) //nolint:mnd,gomnd
+	}
+
 	return &Counters{
-		latencyMillis: hdrhistogram.New(1, 1e6, 3), //nolint:mnd,gomnd
+		latencyMillis:  hdrhistogram.New(1, 1e6, 3), //nolint:mnd,gomnd
+		lifetimeMillis: hdrhistogram.New(1, 1e8, 3), //nolint:mnd,gomnd
+		timingsMicros:  timingsMicros,
+		errorSamples:   make(map[string]*ErrorSample),
+		byCode:         newBreakdowns(),
+		byStage:        newBreakdowns(),
 	}
 }
 
@@ -49,6 +93,35 @@ func (c *Counters) LatencyAtPercentile(percentile float64) int64 {
 	return c.latencyMillis.ValueAtPercentile(percentile)
 }
 
+func (c *Counters) recordTimings(timings tracking.Timings) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for phase, duration := range timings.Phases {
+		histogram, ok := c.timingsMicros[phase]
+		if !ok {
+			continue
+		}
+
+		if err := histogram.RecordValue(duration.Microseconds()); err != nil {
+			log.Warn().Err(err).Str("phase", phase).Msg("failed to record timing")
+		}
+	}
+}
+
+// TimingAtPercentile returns duration of a request phase, see tracking.Phases and tracking.StreamPhases.
+func (c *Counters) TimingAtPercentile(phase string, percentile float64) time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	histogram, ok := c.timingsMicros[phase]
+	if !ok {
+		return 0
+	}
+
+	return time.Duration(histogram.ValueAtPercentile(percentile)) * time.Microsecond
+}
+
 func (c *Counters) Counter(key string) int64 {
 	val, ok := c.m.Load(key)
 	if !ok {
@@ -59,25 +132,199 @@ func (c *Counters) Counter(key string) int64 {
 }
 
 func (c *Counters) incCounter(key string) {
+	c.addCounter(key, 1)
+}
+
+func (c *Counters) addCounter(key string, delta int64) {
 	val, _ := c.m.LoadOrStore(key, new(int64))
-	atomic.AddInt64(val.(*int64), 1) //nolint:forcetypeassert
+	atomic.AddInt64(val.(*int64), delta) //nolint:forcetypeassert
+}
+
+// ErrorCategories returns number of failed requests per category, see tracking.ClassifyError.
+func (c *Counters) ErrorCategories() map[string]int64 {
+	return c.countersWithPrefix(counterErrorPrefix)
+}
+
+// Protocols returns number of responses per negotiated protocol, e.g. HTTP/2.0.
+func (c *Counters) Protocols() map[string]int64 {
+	return c.countersWithPrefix(counterProtocolPrefix)
+}
+
+// Metrics returns totals of custom metrics reported by scenario scripts.
+func (c *Counters) Metrics() map[string]int64 {
+	return c.countersWithPrefix(counterMetricPrefix)
+}
+
+func (c *Counters) countersWithPrefix(prefix string) map[string]int64 {
+	result := make(map[string]int64)
+
+	c.m.Range(func(key, value any) bool {
+		name, _ := key.(string)
+		if suffix, ok := strings.CutPrefix(name, prefix); ok {
+			result[suffix] = atomic.LoadInt64(value.(*int64)) //nolint:forcetypeassert
+		}
+
+		return true
+	})
+
+	return result
+}
+
+// recordErrorSample counts errors by category and type, the first message of a kind is kept as a sample,
+// as messages differ by addresses and ports.
+func (c *Counters) recordErrorSample(category string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := errorSampleKey(category, err)
+
+	sample, ok := c.errorSamples[key]
+	if !ok {
+		if len(c.errorSamples) >= maxErrorSamples {
+			return
+		}
+
+		sample = &ErrorSample{Message: err.Error()}
+		c.errorSamples[key] = sample
+	}
+
+	sample.Count++
+}
+
+// errorSampleKey is an error category with type of the innermost error,
+// errors wrapping several ones, e.g. a category and a cause, are unwrapped by the last one.
+func errorSampleKey(category string, err error) string {
+	for {
+		switch wrapped := err.(type) { //nolint:errorlint
+		case interface{ Unwrap() error }:
+			if next := wrapped.Unwrap(); next != nil {
+				err = next
+
+				continue
+			}
+		case interface{ Unwrap() []error }:
+			if errs := wrapped.Unwrap(); len(errs) > 0 {
+				err = errs[len(errs)-1]
+
+				continue
+			}
+		}
+
+		return category + " " + fmt.Sprintf("%T", err)
+	}
+}
+
+// ErrorSamples returns up to maxErrorSamples samples of distinct kinds of errors, most frequent first.
+func (c *Counters) ErrorSamples() []ErrorSample {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	samples := make([]ErrorSample, 0, len(c.errorSamples))
+	for _, sample := range c.errorSamples {
+		samples = append(samples, *sample)
+	}
+
+	sort.Slice(samples, func(i, j int) bool {
+		if samples[i].Count != samples[j].Count {
+			return samples[i].Count > samples[j].Count
+		}
+
+		return samples[i].Message < samples[j].Message
+	})
+
+	return samples
+}
+
+// CodeBreakdown returns stats per status code, failed requests without response are counted as "error".
+func (c *Counters) CodeBreakdown(percentiles ...float64) []BreakdownStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result := c.byCode.snapshot(percentiles)
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+// StageBreakdown returns stats per stage in order of execution.
+func (c *Counters) StageBreakdown(percentiles ...float64) []BreakdownStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.byStage.snapshot(percentiles)
+}
+
+func (c *Counters) trackBreakdowns(info *tracking.RequestInfo, success bool, latency *time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if latency == nil {
+		// failures with a status code, e.g. of a graphql error, are counted under the code
+		code := info.Code
+		if code == "" {
+			code = codeError
+		}
+
+		c.byCode.trackFailed(code, code)
+
+		if info.Stage != "" {
+			c.byStage.trackFailed(info.Stage, code)
+		}
+
+		return
+	}
+
+	c.byCode.trackResponse(info.Code, info.Code, success, *latency)
+
+	if info.Stage != "" {
+		c.byStage.trackResponse(info.Stage, info.Code, success, *latency)
+	}
+}
+
+func (c *Counters) trackConnection(timings tracking.Timings) {
+	if timings.ConnOpened {
+		c.incCounter(CounterConnections)
+	}
+
+	if !timings.TLSHandshake {
+		return
+	}
+
+	c.incCounter(CounterTLSHandshakes)
+
+	if timings.TLSResumed {
+		c.incCounter(CounterTLSResumed)
+	}
 }
 
 func (c *Counters) TrackFailed(
-	_ *tracking.RequestInfo,
+	info *tracking.RequestInfo,
 	err error,
 ) {
 	c.incCounter(CounterTotal)
 	c.incCounter(CounterFailed)
+	c.trackConnection(info.Timings)
 
-	var netErr net.Error
-	if errors.As(err, &netErr) && netErr.Timeout() {
+	if info.ErrorCategory == tracking.ErrorTimeout {
 		c.incCounter(CounterTimeout)
 	}
+
+	if info.ErrorCategory != "" {
+		c.incCounter(counterErrorPrefix + info.ErrorCategory)
+	}
+
+	if err != nil {
+		c.recordErrorSample(info.ErrorCategory, err)
+	}
+
+	c.trackBreakdowns(info, false, nil)
 }
 
 func (c *Counters) TrackResponse(
-	_ *tracking.RequestInfo,
+	info *tracking.RequestInfo,
 	success bool,
 	latency time.Duration,
 ) {
@@ -90,5 +337,30 @@ func (c *Counters) TrackResponse(
 		c.incCounter(CounterFailed)
 	}
 
+	if info.Timings.ConnReused {
+		c.incCounter(CounterReused)
+	}
+
+	c.trackConnection(info.Timings)
+
+	if info.Protocol != "" {
+		c.incCounter(counterProtocolPrefix + info.Protocol)
+	}
+
+	c.addCounter(CounterBytesSent, info.Bytes.Sent())
+	c.addCounter(CounterBytesReceived, info.Bytes.Received())
+	c.addCounter(CounterBytesReceivedDecoded, info.Bytes.ReceivedDecoded())
+
 	c.recordLatency(latency)
+	c.recordTimings(info.Timings)
+
+	if info.Stream != nil {
+		c.trackStream(info.Stream)
+	}
+
+	for name, value := range info.Metrics {
+		c.addCounter(counterMetricPrefix+name, value)
+	}
+
+	c.trackBreakdowns(info, success, &latency)
 }
@@ -0,0 +1,215 @@
+package stats_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/lameaux/bro/internal/client/stats"
+	"github.com/lameaux/bro/internal/client/tracking"
+)
+
+func TestCounters_TrackResponse(t *testing.T) {
+	t.Parallel()
+
+	counters := stats.NewCounters()
+
+	counters.TrackResponse(&tracking.RequestInfo{
+		Timings: tracking.Timings{
+			Phases: map[string]time.Duration{
+				tracking.PhaseDNS:  2 * time.Millisecond,
+				tracking.PhaseTTFB: 10 * time.Millisecond,
+			},
+			ConnOpened:   true,
+			TLSHandshake: true,
+		},
+	}, true, 15*time.Millisecond)
+
+	counters.TrackResponse(&tracking.RequestInfo{
+		Timings: tracking.Timings{
+			Phases: map[string]time.Duration{
+				tracking.PhaseTTFB: 10 * time.Millisecond,
+			},
+			ConnReused: true,
+		},
+	}, false, 12*time.Millisecond)
+
+	if total := counters.Counter(stats.CounterTotal); total != 2 {
+		t.Errorf("total equals %d; expected 2", total)
+	}
+
+	if reused := counters.Counter(stats.CounterReused); reused != 1 {
+		t.Errorf("reused equals %d; expected 1", reused)
+	}
+
+	if connections := counters.Counter(stats.CounterConnections); connections != 1 {
+		t.Errorf("connections equals %d; expected 1", connections)
+	}
+
+	if handshakes := counters.Counter(stats.CounterTLSHandshakes); handshakes != 1 {
+		t.Errorf("handshakes equals %d; expected 1", handshakes)
+	}
+
+	if dns := counters.TimingAtPercentile(tracking.PhaseDNS, 50).Round(time.Millisecond); dns != 2*time.Millisecond {
+		t.Errorf("dns equals %v; expected 2ms", dns)
+	}
+
+	if ttfb := counters.TimingAtPercentile(tracking.PhaseTTFB, 99).Round(time.Millisecond); ttfb != 10*time.Millisecond {
+		t.Errorf("ttfb equals %v; expected 10ms", ttfb)
+	}
+
+	if unknown := counters.TimingAtPercentile("unknown", 99); unknown != 0 {
+		t.Errorf("unknown equals %v; expected 0", unknown)
+	}
+}
+
+func TestCounters_TrackFailed(t *testing.T) {
+	t.Parallel()
+
+	counters := stats.NewCounters()
+
+	for i := range 20 {
+		counters.TrackFailed(
+			&tracking.RequestInfo{ErrorCategory: tracking.ErrorTimeout},
+			fmt.Errorf("dial 10.0.0.%d:%d: %w", i%15, 8000+i, os.ErrDeadlineExceeded),
+		)
+	}
+
+	for i := range 15 {
+		counters.TrackFailed(
+			&tracking.RequestInfo{ErrorCategory: fmt.Sprintf("category%d", i)},
+			errors.New("failed"), //nolint:err113
+		)
+	}
+
+	if timeout := counters.Counter(stats.CounterTimeout); timeout != 20 {
+		t.Errorf("timeout equals %d; expected 20", timeout)
+	}
+
+	categories := counters.ErrorCategories()
+	if categories[tracking.ErrorTimeout] != 20 || len(categories) != 16 {
+		t.Errorf("categories equals %v; expected 20 timeouts", categories)
+	}
+
+	samples := counters.ErrorSamples()
+	if len(samples) != 10 {
+		t.Fatalf("got %d samples; expected 10", len(samples))
+	}
+
+	// timeouts to different addresses are one kind of error
+	if samples[0].Count != 20 || samples[0].Message != "dial 10.0.0.0:8000: i/o timeout" {
+		t.Errorf("unexpected top sample %+v", samples[0])
+	}
+}
+
+func TestCounters_Breakdowns(t *testing.T) {
+	t.Parallel()
+
+	counters := stats.NewCounters()
+
+	counters.TrackResponse(&tracking.RequestInfo{Code: "200", Stage: "warmup"}, true, time.Millisecond)
+	counters.TrackResponse(&tracking.RequestInfo{Code: "503", Stage: "spike"}, false, time.Millisecond)
+	counters.TrackResponse(&tracking.RequestInfo{Code: "503", Stage: "spike"}, false, time.Millisecond)
+	counters.TrackFailed(&tracking.RequestInfo{Stage: "spike"}, nil)
+	counters.TrackFailed(&tracking.RequestInfo{Code: "503", Stage: "spike"}, nil)
+
+	codes := counters.CodeBreakdown(99)
+	if len(codes) != 3 {
+		t.Fatalf("got %d codes; expected 3", len(codes))
+	}
+
+	if codes[0].Name != "200" || codes[1].Name != "503" || codes[1].Total != 3 || codes[1].Failed != 3 ||
+		codes[2].Name != "error" {
+		t.Errorf("unexpected codes %v", codes)
+	}
+
+	stages := counters.StageBreakdown(99)
+	if len(stages) != 2 {
+		t.Fatalf("got %d stages; expected 2", len(stages))
+	}
+
+	spike := stages[1]
+	if spike.Name != "spike" || spike.Total != 4 || spike.Failed != 4 || spike.Codes["503"] != 3 ||
+		spike.Codes["error"] != 1 {
+		t.Errorf("unexpected spike stage %v", spike)
+	}
+}
+
+func TestCounters_TrackConnection(t *testing.T) {
+	t.Parallel()
+
+	counters := stats.NewCounters()
+
+	counters.TrackConnection(&tracking.ConnectionInfo{CloseCode: 1000, Normal: true, Lifetime: time.Second})
+	counters.TrackConnection(&tracking.ConnectionInfo{CloseCode: 1000, Normal: true, Lifetime: 3 * time.Second})
+	counters.TrackConnection(&tracking.ConnectionInfo{CloseCode: 1006, Lifetime: 2 * time.Second})
+
+	if closed := counters.Counter(stats.CounterClosed); closed != 3 {
+		t.Errorf("closed equals %d; expected 3", closed)
+	}
+
+	codes := counters.CloseCodes()
+	if codes["1000"] != 2 || codes["1006"] != 1 {
+		t.Errorf("unexpected close codes: %v", codes)
+	}
+
+	if lifetime := counters.LifetimeAtPercentile(100); lifetime < 2999 || lifetime > 3001 {
+		t.Errorf("lifetime equals %d; expected 3000", lifetime)
+	}
+}
+
+func TestCounters_TrackStream(t *testing.T) {
+	t.Parallel()
+
+	counters := stats.NewCounters()
+
+	counters.TrackResponse(&tracking.RequestInfo{
+		Code: "200",
+		Timings: tracking.Timings{Phases: map[string]time.Duration{
+			tracking.PhaseFirstEvent: 20 * time.Millisecond,
+			tracking.PhaseStream:     time.Second,
+		}},
+		Stream: &tracking.StreamInfo{
+			Events: 3,
+			Gaps:   []time.Duration{100 * time.Millisecond, 300 * time.Millisecond},
+		},
+	}, true, 10*time.Millisecond)
+
+	if streams := counters.Counter(stats.CounterStreams); streams != 1 {
+		t.Errorf("streams equal %d; expected 1", streams)
+	}
+
+	if events := counters.Counter(stats.CounterEvents); events != 3 {
+		t.Errorf("events equal %d; expected 3", events)
+	}
+
+	gap := counters.TimingAtPercentile(tracking.PhaseEventGap, 100)
+	if gap < 299*time.Millisecond || gap > 301*time.Millisecond {
+		t.Errorf("event gap equals %v; expected 300ms", gap)
+	}
+
+	firstEvent := counters.TimingAtPercentile(tracking.PhaseFirstEvent, 50)
+	if firstEvent < 19*time.Millisecond || firstEvent > 21*time.Millisecond {
+		t.Errorf("first event equals %v; expected 20ms", firstEvent)
+	}
+}
+
+func TestCounters_Metrics(t *testing.T) {
+	t.Parallel()
+
+	counters := stats.NewCounters()
+
+	for _, added := range []int64{2, 3} {
+		counters.TrackResponse(&tracking.RequestInfo{
+			Code:    "200",
+			Metrics: map[string]int64{"itemsAdded": added},
+		}, true, 10*time.Millisecond)
+	}
+
+	metrics := counters.Metrics()
+	if len(metrics) != 1 || metrics["itemsAdded"] != 5 {
+		t.Errorf("metrics equal %v; expected itemsAdded: 5", metrics)
+	}
+}
@@ -2,9 +2,7 @@ package stats
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"net"
 	"sync"
 	"time"
 
@@ -32,11 +30,14 @@ type TrackInfo struct {
 	URL      string
 	Code     string
 
-	Failed  bool
-	Timeout bool
-	Success bool
+	Failed        bool
+	Timeout       bool
+	Success       bool
+	ErrorCategory string
 
 	Latency time.Duration
+	Timings tracking.Timings
+	Bytes   tracking.Bytes
 }
 
 func NewSender(serverAddr, group string) (*Sender, error) {
@@ -105,8 +106,16 @@ func (s *Sender) sendTracking(ctx context.Context) error {
 			Failed:  info.Failed,
 			Timeout: info.Timeout,
 			Success: info.Success,
+			Error:   info.ErrorCategory,
 
 			LatencySeconds: info.Latency.Seconds(),
+
+			TimingSeconds: timingSeconds(info.Timings),
+			ConnReused:    info.Timings.ConnReused,
+
+			BytesSent:            info.Bytes.Sent(),
+			BytesReceived:        info.Bytes.Received(),
+			BytesReceivedDecoded: info.Bytes.ReceivedDecoded(),
 		})
 		if err != nil {
 			return fmt.Errorf("failed to send metric: %w", err)
@@ -124,17 +133,16 @@ func (s *Sender) sendTracking(ctx context.Context) error {
 
 func (s *Sender) TrackFailed(
 	reqInfo *tracking.RequestInfo,
-	err error,
+	_ error,
 ) {
-	var netErr net.Error
-	timeout := errors.As(err, &netErr) && netErr.Timeout()
-
 	info := TrackInfo{
-		Scenario: reqInfo.Scenario,
-		Method:   reqInfo.Method,
-		URL:      reqInfo.URL,
-		Failed:   true,
-		Timeout:  timeout,
+		Scenario:      reqInfo.Scenario,
+		Method:        reqInfo.Method,
+		URL:           reqInfo.URL,
+		Code:          reqInfo.Code,
+		Failed:        true,
+		Timeout:       reqInfo.ErrorCategory == tracking.ErrorTimeout,
+		ErrorCategory: reqInfo.ErrorCategory,
 	}
 
 	s.mu.Lock()
@@ -154,9 +162,25 @@ func (s *Sender) TrackResponse(
 		Code:     reqInfo.Code,
 		Success:  success,
 		Latency:  latency,
+		Timings:  reqInfo.Timings,
+		Bytes:    reqInfo.Bytes,
 	}
 
 	s.mu.Lock()
 	s.queue = append(s.queue, info)
 	s.mu.Unlock()
 }
+
+func timingSeconds(timings tracking.Timings) map[string]float64 {
+	if len(timings.Phases) == 0 {
+		return nil
+	}
+
+	result := make(map[string]float64, len(timings.Phases))
+
+	for phase, duration := range timings.Phases {
+		result[phase] = duration.Seconds()
+	}
+
+	return result
+}
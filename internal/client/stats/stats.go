@@ -92,3 +92,18 @@ func (s *Stats) Rps(scenarioName string) float64 {
 
 	return math.Round(float64(total) / duration.Seconds())
 }
+
+const bytesInMegabyte = 1e6
+
+// Throughput returns MB/s for one of byte counters, e.g. CounterBytesReceived.
+func (s *Stats) Throughput(scenarioName string, counter string) float64 {
+	return Throughput(s.Counters(scenarioName).Counter(counter), s.Duration(scenarioName))
+}
+
+func Throughput(bytes int64, duration time.Duration) float64 {
+	if duration <= 0 {
+		return 0
+	}
+
+	return float64(bytes) / bytesInMegabyte / duration.Seconds()
+}
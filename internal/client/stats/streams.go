@@ -0,0 +1,23 @@
+package stats
+
+import (
+	"github.com/lameaux/bro/internal/client/tracking"
+	"github.com/rs/zerolog/log"
+)
+
+// trackStream counts events of a streaming response and records gaps between them.
+func (c *Counters) trackStream(info *tracking.StreamInfo) {
+	c.incCounter(CounterStreams)
+	c.addCounter(CounterEvents, info.Events)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	histogram := c.timingsMicros[tracking.PhaseEventGap]
+
+	for _, gap := range info.Gaps {
+		if err := histogram.RecordValue(gap.Microseconds()); err != nil {
+			log.Warn().Err(err).Msg("failed to record event gap")
+		}
+	}
+}
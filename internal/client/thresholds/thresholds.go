@@ -3,29 +3,52 @@ package thresholds
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/lameaux/bro/internal/client/checker"
 	"github.com/lameaux/bro/internal/client/config"
 	"github.com/lameaux/bro/internal/client/stats"
+	"github.com/lameaux/bro/internal/client/tracking"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
 
 const (
-	metricChecks  = "checks"
-	metricLatency = "latency"
+	metricChecks     = "checks"
+	metricLatency    = "latency"
+	metricBytes      = "bytes"
+	metricThroughput = "throughput"
+	// metricCustom is a total of a custom metric reported by a scenario script, its name is a threshold type.
+	metricCustom = "custom"
+
+	typeSent            = "sent"
+	typeReceived        = "received"
+	typeReceivedDecoded = "receivedDecoded"
 )
 
-var errMissingCheckCounters = errors.New("missing check counters")
+var (
+	errMissingCheckCounters = errors.New("missing check counters")
+	errInvalidBytesType     = errors.New("invalid bytes type")
+)
 
+// CheckCounters counts passed checks of a scenario run by check type, including checks which are not
+// defined on scenario level, e.g. websocket message checks.
 type CheckCounters struct {
 	mu     sync.RWMutex
 	passed map[string]int64
 	total  map[string]int64
 }
 
+func NewCheckCounters() *CheckCounters {
+	return &CheckCounters{
+		passed: make(map[string]int64),
+		total:  make(map[string]int64),
+	}
+}
+
 func (cc *CheckCounters) Inc(checkType string, passed bool) {
 	cc.mu.Lock()
 	defer cc.mu.Unlock()
@@ -62,67 +85,110 @@ func (cc *CheckCounters) Rate(checkType string) float64 {
 	return float64(cc.passed[checkType]) / float64(cc.total[checkType])
 }
 
-// FIXME: refactor into struct.
-var scenarioCounters = make(map[string]*CheckCounters) //nolint:gochecknoglobals
-
-func AddScenario(scenario *config.Scenario) {
-	scenarioCounters[scenario.Name] = &CheckCounters{
-		passed: make(map[string]int64),
-		total:  make(map[string]int64),
+// Update counts results of checks by check type.
+func (cc *CheckCounters) Update(checks []*config.Check, results []checker.Result) {
+	for i, check := range checks {
+		cc.Inc(check.Type, results[i].Pass)
 	}
 }
 
-func UpdateScenario(
+func ValidateScenario(
 	scenario *config.Scenario,
-	results []checker.Result,
-) {
-	checkCounters := scenarioCounters[scenario.Name]
+	checkCounters *CheckCounters,
+	counters *stats.Counters,
+	duration time.Duration,
+) (bool, error) {
+	success := true
+
+	for _, threshold := range scenario.Thresholds {
+		passed, err := validateThreshold(scenario, threshold, checkCounters, counters, duration)
+		if err != nil {
+			return false, err
+		}
 
-	for i, check := range scenario.Checks {
-		result := results[i]
-		checkCounters.Inc(check.Type, result.Pass)
+		if !passed {
+			success = false
+		}
 	}
+
+	return success, nil
 }
 
-func ValidateScenario(
+func validateThreshold( //nolint:cyclop
 	scenario *config.Scenario,
+	threshold *config.Threshold,
+	checkCounters *CheckCounters,
 	counters *stats.Counters,
+	duration time.Duration,
 ) (bool, error) {
-	success := true
+	switch {
+	case threshold.Metric == metricChecks:
+		passed, err := validateMetricCheck(scenario, threshold, checkCounters)
+		if err != nil {
+			return false, fmt.Errorf("failed to validate metric check: %w", err)
+		}
 
-	for _, threshold := range scenario.Thresholds {
-		if threshold.Metric == metricChecks {
-			passed, err := validateMetricCheck(scenario, threshold)
-			if err != nil {
-				return false, fmt.Errorf("failed to validate metric check: %w", err)
-			}
-
-			if !passed {
-				success = false
-			}
+		return passed, nil
+	case threshold.Metric == metricLatency:
+		passed, err := validatePercentileCheck(scenario, threshold, func(percentile float64) float64 {
+			return float64(counters.LatencyAtPercentile(percentile))
+		})
+		if err != nil {
+			return false, fmt.Errorf("failed to validate latency check: %w", err)
 		}
 
-		if threshold.Metric == metricLatency {
-			passed, err := validateLatencyCheck(scenario, threshold, counters)
-			if err != nil {
-				return false, fmt.Errorf("failed to validate latency check: %w", err)
-			}
+		return passed, nil
+	case slices.Contains(tracking.Phases, threshold.Metric), slices.Contains(tracking.StreamPhases, threshold.Metric):
+		passed, err := validatePercentileCheck(scenario, threshold, func(percentile float64) float64 {
+			timing := counters.TimingAtPercentile(threshold.Metric, percentile)
 
-			if !passed {
-				success = false
-			}
+			return float64(timing.Microseconds()) / float64(time.Millisecond/time.Microsecond)
+		})
+		if err != nil {
+			return false, fmt.Errorf("failed to validate timing check: %w", err)
 		}
+
+		return passed, nil
+	case threshold.Metric == metricBytes:
+		counter, err := bytesCounter(threshold.Type)
+		if err != nil {
+			return false, fmt.Errorf("failed to validate bytes check: %w", err)
+		}
+
+		return validateValueCheck(scenario, threshold, float64(counters.Counter(counter))), nil
+	case threshold.Metric == metricThroughput:
+		counter, err := bytesCounter(threshold.Type)
+		if err != nil {
+			return false, fmt.Errorf("failed to validate throughput check: %w", err)
+		}
+
+		return validateValueCheck(scenario, threshold, stats.Throughput(counters.Counter(counter), duration)), nil
+	case threshold.Metric == metricCustom:
+		return validateValueCheck(scenario, threshold, float64(counters.Metrics()[threshold.Type])), nil
 	}
 
-	return success, nil
+	return true, nil
+}
+
+func bytesCounter(thresholdType string) (string, error) {
+	switch thresholdType {
+	case typeSent:
+		return stats.CounterBytesSent, nil
+	case typeReceived:
+		return stats.CounterBytesReceived, nil
+	case typeReceivedDecoded:
+		return stats.CounterBytesReceivedDecoded, nil
+	}
+
+	return "", fmt.Errorf("%w: %s", errInvalidBytesType, thresholdType)
 }
 
 func validateMetricCheck(
 	scenario *config.Scenario,
 	threshold *config.Threshold,
+	checkCounters *CheckCounters,
 ) (bool, error) {
-	checkCounters, ok := scenarioCounters[scenario.Name]
-	if !ok {
+	if checkCounters == nil {
 		return false, errMissingCheckCounters
 	}
 
@@ -153,19 +219,25 @@ func validateMetricCheck(
 	return passed, nil
 }
 
-func validateLatencyCheck(
+func validatePercentileCheck(
 	scenario *config.Scenario,
 	threshold *config.Threshold,
-	counters *stats.Counters,
+	valueAtPercentile func(percentile float64) float64,
 ) (bool, error) {
 	percentile, err := strconv.ParseFloat(threshold.Type, 64)
 	if err != nil {
 		return false, fmt.Errorf("invalid percentile: %w", err)
 	}
 
-	passed := true
+	return validateValueCheck(scenario, threshold, valueAtPercentile(percentile)), nil
+}
 
-	value := float64(counters.LatencyAtPercentile(percentile))
+func validateValueCheck(
+	scenario *config.Scenario,
+	threshold *config.Threshold,
+	value float64,
+) bool {
+	passed := true
 
 	if threshold.MinValue != nil && *threshold.MinValue > value {
 		passed = false
@@ -177,7 +249,7 @@ func validateLatencyCheck(
 
 	logThresholdValidation(scenario, threshold, passed, 0, 0, value)
 
-	return passed, nil
+	return passed
 }
 
 func logThresholdValidation(
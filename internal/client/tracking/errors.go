@@ -0,0 +1,124 @@
+package tracking
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	ErrorDNS              = "dns"
+	ErrorConnRefused      = "connectionRefused"
+	ErrorPortExhausted    = "portExhausted"
+	ErrorConnReset        = "connectionReset"
+	ErrorTLS              = "tls"
+	ErrorTimeout          = "timeout"
+	ErrorCanceled         = "canceled"
+	ErrorTooManyRedirects = "tooManyRedirects"
+	ErrorBodyRead         = "bodyRead"
+	ErrorUnavailable      = "unavailable"
+	ErrorGraphQL          = "graphql"
+	ErrorScript           = "script"
+	ErrorOther            = "other"
+)
+
+var (
+	ErrBodyRead = errors.New("failed to read response body")
+	// ErrGraphQL is returned for responses with errors array, status code of such responses is usually 200.
+	ErrGraphQL = errors.New("graphql error")
+	// ErrScript is returned when a hook of a scenario script throws an exception.
+	ErrScript = errors.New("script error")
+)
+
+// ClassifyError returns a category of a transport error.
+func ClassifyError(err error) string { //nolint:cyclop
+	var (
+		netErr       net.Error
+		dnsErr       *net.DNSError
+		tlsRecordErr tls.RecordHeaderError
+		tlsAlertErr  tls.AlertError
+		tlsVerifyErr *tls.CertificateVerificationError
+		authorityErr x509.UnknownAuthorityError
+		hostnameErr  x509.HostnameError
+		certErr      x509.CertificateInvalidError
+	)
+
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrBodyRead):
+		return ErrorBodyRead
+	case errors.Is(err, ErrGraphQL):
+		return ErrorGraphQL
+	case errors.Is(err, ErrScript):
+		return ErrorScript
+	case isGRPCStatus(err):
+		return classifyGRPCStatus(status.Convert(err))
+	case errors.Is(err, context.Canceled):
+		return ErrorCanceled
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorTimeout
+	case errors.As(err, &dnsErr):
+		return ErrorDNS
+	case errors.Is(err, syscall.EADDRNOTAVAIL), errors.Is(err, syscall.EADDRINUSE):
+		// no free ephemeral ports left for the local address
+		return ErrorPortExhausted
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return ErrorConnRefused
+	case errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.EPIPE),
+		errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return ErrorConnReset
+	case errors.As(err, &tlsRecordErr), errors.As(err, &tlsAlertErr), errors.As(err, &tlsVerifyErr),
+		errors.As(err, &authorityErr), errors.As(err, &hostnameErr), errors.As(err, &certErr):
+		return ErrorTLS
+	case isTooManyRedirects(err):
+		return ErrorTooManyRedirects
+	default:
+		return ErrorOther
+	}
+}
+
+// net/http does not export an error for redirect limit.
+func isTooManyRedirects(err error) bool {
+	msg := err.Error()
+
+	return strings.Contains(msg, "stopped after") && strings.Contains(msg, "redirects")
+}
+
+func isGRPCStatus(err error) bool {
+	var grpcErr interface{ GRPCStatus() *status.Status }
+
+	return errors.As(err, &grpcErr)
+}
+
+// grpc reports transport errors as Unavailable status with a text description.
+func classifyGRPCStatus(st *status.Status) string {
+	msg := st.Message()
+
+	switch {
+	case st.Code() == codes.DeadlineExceeded:
+		return ErrorTimeout
+	case st.Code() == codes.Canceled:
+		return ErrorCanceled
+	case st.Code() != codes.Unavailable:
+		return ErrorOther
+	case strings.Contains(msg, "connection refused"):
+		return ErrorConnRefused
+	case strings.Contains(msg, "no such host"), strings.Contains(msg, "produced zero addresses"):
+		return ErrorDNS
+	case strings.Contains(msg, "tls:"), strings.Contains(msg, "x509:"), strings.Contains(msg, "handshake"):
+		return ErrorTLS
+	case strings.Contains(msg, "connection reset"), strings.Contains(msg, "EOF"):
+		return ErrorConnReset
+	default:
+		return ErrorUnavailable
+	}
+}
@@ -0,0 +1,122 @@
+package tracking_test
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/lameaux/bro/internal/client/tracking"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var errUnknown = errors.New("unknown")
+
+func TestClassifyError(t *testing.T) {
+	t.Parallel()
+
+	wrap := func(err error) error {
+		return &url.Error{Op: "Get", URL: "http://localhost", Err: err}
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		category string
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name:     "dns",
+			err:      wrap(&net.DNSError{Err: "no such host", Name: "unknown"}),
+			category: tracking.ErrorDNS,
+		},
+		{
+			name:     "connection refused",
+			err:      wrap(&net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}),
+			category: tracking.ErrorConnRefused,
+		},
+		{
+			name:     "port exhausted",
+			err:      wrap(&net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.EADDRNOTAVAIL)}),
+			category: tracking.ErrorPortExhausted,
+		},
+		{
+			name:     "grpc connection refused",
+			err:      status.Error(codes.Unavailable, "connection error: desc = \"dial tcp: connect: connection refused\""),
+			category: tracking.ErrorConnRefused,
+		},
+		{
+			name:     "grpc deadline",
+			err:      status.Error(codes.DeadlineExceeded, "context deadline exceeded"),
+			category: tracking.ErrorTimeout,
+		},
+		{
+			name:     "grpc unavailable",
+			err:      status.Error(codes.Unavailable, "server is shutting down"),
+			category: tracking.ErrorUnavailable,
+		},
+		{
+			name:     "connection reset",
+			err:      wrap(&net.OpError{Op: "read", Err: syscall.ECONNRESET}),
+			category: tracking.ErrorConnReset,
+		},
+		{
+			name:     "tls",
+			err:      wrap(x509.UnknownAuthorityError{}),
+			category: tracking.ErrorTLS,
+		},
+		{
+			name:     "timeout",
+			err:      wrap(context.DeadlineExceeded),
+			category: tracking.ErrorTimeout,
+		},
+		{
+			name:     "canceled",
+			err:      wrap(context.Canceled),
+			category: tracking.ErrorCanceled,
+		},
+		{
+			name:     "too many redirects",
+			err:      wrap(errors.New("stopped after 10 redirects")), //nolint:err113
+			category: tracking.ErrorTooManyRedirects,
+		},
+		{
+			name:     "body read",
+			err:      fmt.Errorf("%w: %w", tracking.ErrBodyRead, syscall.ECONNRESET),
+			category: tracking.ErrorBodyRead,
+		},
+		{
+			name:     "graphql",
+			err:      fmt.Errorf("%w: Cannot query field \"user\"", tracking.ErrGraphQL),
+			category: tracking.ErrorGraphQL,
+		},
+		{
+			name:     "script",
+			err:      fmt.Errorf("%w: ReferenceError: cart is not defined", tracking.ErrScript),
+			category: tracking.ErrorScript,
+		},
+		{
+			name:     "other",
+			err:      wrap(errUnknown),
+			category: tracking.ErrorOther,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if category := tracking.ClassifyError(tt.err); category != tt.category {
+				t.Errorf("category equals %v; expected %v", category, tt.category)
+			}
+		})
+	}
+}
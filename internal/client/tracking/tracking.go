@@ -1,8 +1,104 @@
 package tracking
 
+import "time"
+
+const (
+	PhaseDNS      = "dns"
+	PhaseConnect  = "connect"
+	PhaseTLS      = "tls"
+	PhaseTTFB     = "ttfb"
+	PhaseDownload = "download"
+)
+
+const (
+	PhaseFirstEvent = "firstEvent"
+	PhaseEventGap   = "eventGap"
+	PhaseStream     = "stream"
+)
+
+//nolint:gochecknoglobals
+var Phases = []string{PhaseDNS, PhaseConnect, PhaseTLS, PhaseTTFB, PhaseDownload}
+
+// StreamPhases are recorded for streaming responses, firstEvent is measured like ttfb
+// and stream from the first byte until the end of the stream.
+//
+//nolint:gochecknoglobals
+var StreamPhases = []string{PhaseFirstEvent, PhaseEventGap, PhaseStream}
+
+// StageInfo describes a stage of a scenario, constant rate scenarios have a single unnamed stage.
+type StageInfo struct {
+	Scenario  string
+	Stage     string
+	StartRPS  int
+	TargetRPS int
+	Threads   int
+	Duration  time.Duration
+}
+
+// ConnectionInfo describes a closed websocket connection.
+type ConnectionInfo struct {
+	Scenario string
+	URL      string
+	Stage    string
+
+	// CloseCode is a close frame status, e.g. 1000 for normal closure or 1006 if connection was dropped.
+	CloseCode int
+	Normal    bool
+	Lifetime  time.Duration
+	Messages  int64
+}
+
 type RequestInfo struct {
 	Scenario string
 	Method   string
 	URL      string
 	Code     string
+	Stage    string
+	Protocol string
+
+	Timings       Timings
+	Bytes         Bytes
+	Stream        *StreamInfo
+	ErrorCategory string
+	// Metrics are custom counters reported by a scenario script.
+	Metrics map[string]int64
+}
+
+// StreamInfo counts events of a streaming response, gaps are durations between consecutive events.
+type StreamInfo struct {
+	Events int64
+	Gaps   []time.Duration
+}
+
+// Bytes counts request and response sizes, ReceivedBody is measured before decompression.
+type Bytes struct {
+	SentHeaders int64
+	SentBody    int64
+
+	ReceivedHeaders     int64
+	ReceivedBody        int64
+	ReceivedBodyDecoded int64
+}
+
+func (b Bytes) Sent() int64 {
+	return b.SentHeaders + b.SentBody
+}
+
+func (b Bytes) Received() int64 {
+	return b.ReceivedHeaders + b.ReceivedBody
+}
+
+func (b Bytes) ReceivedDecoded() int64 {
+	return b.ReceivedHeaders + b.ReceivedBodyDecoded
+}
+
+// Timings is a breakdown of request latency.
+// Phases contains only the phases that took place, e.g. dns is missing for reused connections.
+type Timings struct {
+	Phases     map[string]time.Duration
+	ConnOpened bool
+	ConnReused bool
+
+	TLSHandshake bool
+	TLSResumed   bool
 }
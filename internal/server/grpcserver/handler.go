@@ -40,6 +40,8 @@ func (s *server) Send(stream grpc.ClientStreamingServer[pb.MetricV1, pb.Empty])
 func (s *server) countRequestMetric(metric *pb.MetricV1) {
 	labels := requestLabels(metric)
 	s.promMetrics.CountRequest(labels, metric.GetLatencySeconds())
+	s.promMetrics.ObservePhases(labels, metric.GetTimingSeconds())
+	s.promMetrics.CountBytes(labels, metric.GetBytesSent(), metric.GetBytesReceived(), metric.GetBytesReceivedDecoded())
 }
 
 func requestLabels(metric *pb.MetricV1) map[string]string {
@@ -53,5 +55,7 @@ func requestLabels(metric *pb.MetricV1) map[string]string {
 		"failed":      strconv.FormatBool(metric.GetFailed()),
 		"timeout":     strconv.FormatBool(metric.GetTimeout()),
 		"success":     strconv.FormatBool(metric.GetSuccess()),
+		"error":       metric.GetError(),
+		"conn_reused": strconv.FormatBool(metric.GetConnReused()),
 	}
 }
@@ -16,6 +16,9 @@ var labelsTotal = []string{
 	"failed",
 	"timeout",
 	"success",
+	"error",
+
+	"conn_reused",
 }
 
 //nolint:gochecknoglobals
@@ -30,9 +33,33 @@ var labelsDuration = []string{
 	"success",
 }
 
+//nolint:gochecknoglobals
+var labelsPhase = []string{
+	"group_id",
+
+	"scenario",
+	"method",
+	"url",
+
+	"phase",
+}
+
+//nolint:gochecknoglobals
+var labelsBytes = []string{
+	"group_id",
+
+	"scenario",
+	"method",
+	"url",
+}
+
 type Metrics struct {
-	httpRequestsTotal          *prometheus.CounterVec
-	httpRequestDurationSeconds *prometheus.HistogramVec
+	httpRequestsTotal               *prometheus.CounterVec
+	httpRequestDurationSeconds      *prometheus.HistogramVec
+	httpRequestPhaseDurationSeconds *prometheus.HistogramVec
+	httpRequestBytesTotal           *prometheus.CounterVec
+	httpResponseBytesTotal          *prometheus.CounterVec
+	httpResponseDecodedBytesTotal   *prometheus.CounterVec
 }
 
 func NewMetrics(prefix string) *Metrics {
@@ -52,11 +79,44 @@ func NewMetrics(prefix string) *Metrics {
 			},
 			labelsDuration,
 		),
+		httpRequestPhaseDurationSeconds: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    prefix + "http_request_phase_duration_seconds",
+				Help:    "Duration of HTTP request phases (dns, connect, tls, ttfb, download) in seconds",
+				Buckets: prometheus.DefBuckets,
+			},
+			labelsPhase,
+		),
+		httpRequestBytesTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: prefix + "http_request_bytes_total",
+				Help: "Total number of bytes sent in HTTP requests, including headers",
+			},
+			labelsBytes,
+		),
+		httpResponseBytesTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: prefix + "http_response_bytes_total",
+				Help: "Total number of bytes received in HTTP responses, including headers",
+			},
+			labelsBytes,
+		),
+		httpResponseDecodedBytesTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: prefix + "http_response_decoded_bytes_total",
+				Help: "Total number of bytes received in HTTP responses after decompression, including headers",
+			},
+			labelsBytes,
+		),
 	}
 
 	prometheus.MustRegister(
 		requestMetrics.httpRequestsTotal,
 		requestMetrics.httpRequestDurationSeconds,
+		requestMetrics.httpRequestPhaseDurationSeconds,
+		requestMetrics.httpRequestBytesTotal,
+		requestMetrics.httpResponseBytesTotal,
+		requestMetrics.httpResponseDecodedBytesTotal,
 	)
 
 	return requestMetrics
@@ -67,6 +127,23 @@ func (m *Metrics) CountRequest(labels map[string]string, latency float64) {
 	m.httpRequestDurationSeconds.With(makePromLabels(labels, labelsDuration)).Observe(latency)
 }
 
+func (m *Metrics) ObservePhases(labels map[string]string, phases map[string]float64) {
+	for phase, seconds := range phases {
+		phaseLabels := makePromLabels(labels, labelsPhase)
+		phaseLabels["phase"] = phase
+
+		m.httpRequestPhaseDurationSeconds.With(phaseLabels).Observe(seconds)
+	}
+}
+
+func (m *Metrics) CountBytes(labels map[string]string, sent, received, receivedDecoded int64) {
+	bytesLabels := makePromLabels(labels, labelsBytes)
+
+	m.httpRequestBytesTotal.With(bytesLabels).Add(float64(sent))
+	m.httpResponseBytesTotal.With(bytesLabels).Add(float64(received))
+	m.httpResponseDecodedBytesTotal.With(bytesLabels).Add(float64(receivedDecoded))
+}
+
 func makePromLabels(labels prometheus.Labels, keys []string) prometheus.Labels {
 	result := prometheus.Labels{}
 
@@ -0,0 +1,179 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	errUnknownConnectionStrategy = errors.New("unknown connection strategy")
+	errUnknownCookiesMode        = errors.New("unknown cookies mode")
+	errResetSharedCookies        = errors.New("resetCookies can't be used with shared cookies")
+)
+
+type threadClient struct {
+	client *http.Client
+	// transport is owned by the thread and closed with clients, it is nil for shared connections
+	transport http.RoundTripper
+	jar       *cookieJar
+}
+
+// connectionLimiter closes connection of every n-th request of a thread, so that the next request opens a new one.
+type connectionLimiter struct {
+	http.RoundTripper
+	n        int64
+	requests atomic.Int64
+}
+
+func (l *connectionLimiter) RoundTrip(req *http.Request) (*http.Response, error) {
+	if l.requests.Add(1)%l.n == 0 {
+		// round trippers must not modify requests
+		req = req.Clone(req.Context())
+		req.Close = true
+	}
+
+	return l.RoundTripper.RoundTrip(req) //nolint:wrapcheck
+}
+
+// Clients hands out http clients to sender threads according to connection strategy and cookies mode.
+type Clients struct {
+	conf   config.HTTPClient
+	shared *threadClient
+
+	mu        sync.Mutex
+	perThread map[int]*threadClient
+}
+
+func NewClients(conf config.HTTPClient) (*Clients, error) {
+	switch conf.ConnectionStrategy {
+	case "", config.ConnectionStrategyShared, config.ConnectionStrategyPerThread:
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnknownConnectionStrategy, conf.ConnectionStrategy)
+	}
+
+	switch conf.Cookies {
+	case "", config.CookiesNone, config.CookiesShared, config.CookiesPerThread:
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnknownCookiesMode, conf.Cookies)
+	}
+
+	// a shared jar would be emptied by every thread for all others
+	if conf.ResetCookies && conf.Cookies == config.CookiesShared {
+		return nil, errResetSharedCookies
+	}
+
+	client, err := New(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	shared := &threadClient{client: client}
+
+	if conf.Cookies == config.CookiesShared {
+		shared.jar = newCookieJar()
+		client.Jar = shared.jar
+	}
+
+	return &Clients{
+		conf:      conf,
+		shared:    shared,
+		perThread: make(map[int]*threadClient),
+	}, nil
+}
+
+// Client returns http client to be used by the thread.
+func (c *Clients) Client(threadID int) *http.Client {
+	return c.thread(threadID).client
+}
+
+// RequestDone is called when thread finished an iteration. It empties cookie jar if resetCookies is set.
+func (c *Clients) RequestDone(threadID int) {
+	thread := c.thread(threadID)
+
+	if c.conf.ResetCookies && thread.jar != nil {
+		thread.jar.Reset()
+	}
+}
+
+// Close closes transports of all clients, it is called when clients are no longer used.
+func (c *Clients) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	errs := []error{closeTransport(c.shared.client.Transport)}
+
+	for _, thread := range c.perThread {
+		// threads with own cookie jar or request counter share the transport
+		if thread.transport != nil {
+			errs = append(errs, closeTransport(thread.transport))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *Clients) thread(threadID int) *threadClient {
+	if c.conf.ConnectionStrategy != config.ConnectionStrategyPerThread &&
+		c.conf.Cookies != config.CookiesPerThread &&
+		c.conf.RequestsPerConnection <= 0 {
+		return c.shared
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	thread, ok := c.perThread[threadID]
+	if !ok {
+		thread = c.newThreadClient(threadID)
+		c.perThread[threadID] = thread
+	}
+
+	return thread
+}
+
+func (c *Clients) newThreadClient(threadID int) *threadClient {
+	thread := &threadClient{
+		jar: c.shared.jar,
+	}
+
+	if c.conf.ConnectionStrategy == config.ConnectionStrategyPerThread {
+		client, err := New(c.conf)
+		if err != nil {
+			// config is validated when shared client is created
+			log.Warn().Err(err).Int("threadID", threadID).Msg("failed to create http client, using shared one")
+
+			return c.shared
+		}
+
+		thread.client = client
+		thread.transport = client.Transport
+	} else {
+		// own cookie jar or request counter over shared connections
+		client := *c.shared.client
+		thread.client = &client
+	}
+
+	// requests are counted per thread, so that threads do not close connections of each other
+	if c.conf.RequestsPerConnection > 0 {
+		thread.client.Transport = &connectionLimiter{
+			RoundTripper: thread.client.Transport,
+			n:            int64(c.conf.RequestsPerConnection),
+		}
+	}
+
+	if c.conf.Cookies == config.CookiesPerThread {
+		thread.jar = newCookieJar()
+	}
+
+	if thread.jar != nil {
+		thread.client.Jar = thread.jar
+	}
+
+	return thread
+}
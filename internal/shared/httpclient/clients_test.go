@@ -0,0 +1,210 @@
+package httpclient_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/lameaux/bro/internal/shared/httpclient"
+)
+
+func TestClients(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		conf        config.HTTPClient
+		sameClient  bool
+		connections int64
+	}{
+		{name: "shared", conf: config.HTTPClient{}, sameClient: true, connections: 1},
+		{
+			name:        "per thread",
+			conf:        config.HTTPClient{ConnectionStrategy: config.ConnectionStrategyPerThread},
+			connections: 2,
+		},
+		{
+			// requests are counted per thread over shared connections
+			name:        "requests per connection",
+			conf:        config.HTTPClient{RequestsPerConnection: 2},
+			connections: 3,
+		},
+		{
+			name: "requests per connection per thread",
+			conf: config.HTTPClient{
+				ConnectionStrategy:    config.ConnectionStrategyPerThread,
+				RequestsPerConnection: 2,
+			},
+			connections: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var connections atomic.Int64
+
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+				if state == http.StateNew {
+					connections.Add(1)
+				}
+			}
+			server.Start()
+			t.Cleanup(server.Close)
+
+			clients, err := httpclient.NewClients(tt.conf)
+			if err != nil {
+				t.Fatalf("failed to create clients: %v", err)
+			}
+
+			if same := clients.Client(0) == clients.Client(1); same != tt.sameClient {
+				t.Errorf("got same client %v, want %v", same, tt.sameClient)
+			}
+
+			// requests are sent sequentially, alternating between two threads
+			for i := range 6 {
+				threadID := i % 2
+
+				if err = get(clients.Client(threadID), server.URL); err != nil {
+					t.Fatalf("request failed: %v", err)
+				}
+
+				clients.RequestDone(threadID)
+			}
+
+			if got := connections.Load(); got != tt.connections {
+				t.Errorf("got %d connections, want %d", got, tt.connections)
+			}
+		})
+	}
+}
+
+func TestNewClientsUnknownStrategy(t *testing.T) {
+	t.Parallel()
+
+	if _, err := httpclient.NewClients(config.HTTPClient{ConnectionStrategy: "perRequest"}); err == nil {
+		t.Error("expected error for unknown connection strategy")
+	}
+}
+
+func TestClientsCookies(t *testing.T) {
+	t.Parallel()
+
+	// sets a cookie for the first request of a session and returns 401 if it is missing afterwards
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "1", Path: "/"})
+
+			return
+		}
+
+		if _, err := r.Cookie("session"); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		name string
+		conf config.HTTPClient
+		// codes of /private requests from thread 0, thread 1 and thread 0 again after login of thread 0
+		codes []int
+	}{
+		{
+			name:  "none",
+			conf:  config.HTTPClient{},
+			codes: []int{http.StatusUnauthorized, http.StatusUnauthorized},
+		},
+		{
+			name:  "shared",
+			conf:  config.HTTPClient{Cookies: config.CookiesShared},
+			codes: []int{http.StatusOK, http.StatusOK},
+		},
+		{
+			name:  "per thread",
+			conf:  config.HTTPClient{Cookies: config.CookiesPerThread},
+			codes: []int{http.StatusOK, http.StatusUnauthorized},
+		},
+		{
+			name: "per thread with own connections",
+			conf: config.HTTPClient{
+				Cookies:            config.CookiesPerThread,
+				ConnectionStrategy: config.ConnectionStrategyPerThread,
+			},
+			codes: []int{http.StatusOK, http.StatusUnauthorized},
+		},
+		{
+			name:  "reset",
+			conf:  config.HTTPClient{Cookies: config.CookiesPerThread, ResetCookies: true},
+			codes: []int{http.StatusUnauthorized, http.StatusUnauthorized},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			clients, err := httpclient.NewClients(tt.conf)
+			if err != nil {
+				t.Fatalf("failed to create clients: %v", err)
+			}
+
+			if err = get(clients.Client(0), server.URL+"/login"); err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+
+			clients.RequestDone(0)
+
+			for i, threadID := range []int{0, 1} {
+				code, err := getCode(clients.Client(threadID), server.URL+"/private")
+				if err != nil {
+					t.Fatalf("request failed: %v", err)
+				}
+
+				if code != tt.codes[i] {
+					t.Errorf("thread %d got code %d, want %d", threadID, code, tt.codes[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNewClientsResetSharedCookies(t *testing.T) {
+	t.Parallel()
+
+	if _, err := httpclient.NewClients(config.HTTPClient{Cookies: config.CookiesShared, ResetCookies: true}); err == nil {
+		t.Errorf("expected error for resetCookies with shared cookie jar")
+	}
+}
+
+func TestNewClientsUnknownCookiesMode(t *testing.T) {
+	t.Parallel()
+
+	if _, err := httpclient.NewClients(config.HTTPClient{Cookies: "perRequest"}); err == nil {
+		t.Error("expected error for unknown cookies mode")
+	}
+}
+
+func getCode(client *http.Client, url string) (int, error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err //nolint:wrapcheck
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err //nolint:wrapcheck
+	}
+
+	resp.Body.Close()
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,48 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"sync"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// cookieJar is a cookie jar that can be emptied between iterations.
+type cookieJar struct {
+	mu  sync.RWMutex
+	jar *cookiejar.Jar
+}
+
+func newCookieJar() *cookieJar {
+	return &cookieJar{jar: newStdJar()}
+}
+
+func newStdJar() *cookiejar.Jar {
+	// error is returned only for invalid options
+	jar, _ := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+
+	return jar
+}
+
+func (j *cookieJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	j.jar.SetCookies(u, cookies)
+}
+
+func (j *cookieJar) Cookies(u *url.URL) []*http.Cookie {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	return j.jar.Cookies(u)
+}
+
+func (j *cookieJar) Reset() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.jar = newStdJar()
+}
@@ -0,0 +1,417 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/quic-go/quic-go"
+)
+
+const (
+	defaultDNSPort = "53"
+	anyPort        = "*"
+)
+
+var (
+	errInvalidResolve      = errors.New("invalid resolve entry, expected host:port:ip[,ip]")
+	errNoAddresses         = errors.New("no addresses resolved")
+	errInvalidLocalAddress = errors.New("invalid local address, expected IP or interface name")
+	errNoLocalAddress      = errors.New("no local address of the same family")
+)
+
+type cachedHost struct {
+	ips     []string
+	expires time.Time
+}
+
+// dialer resolves host names with static overrides, custom DNS server and cache,
+// spreads new connections over all resolved addresses and binds them to local addresses.
+type dialer struct {
+	net        net.Dialer
+	resolver   *net.Resolver
+	static     map[string][]string // host:port or host:* to IPs
+	cacheTTL   time.Duration
+	roundRobin bool
+	localIPs   []net.IP
+
+	mu             sync.Mutex
+	cache          map[string]cachedHost
+	next           map[string]int
+	nextLocal      int
+	quicTransports map[string]*quic.Transport // by local IP
+}
+
+// newDialer returns nil when default resolution of net.Dialer is sufficient.
+func newDialer(conf config.HTTPClient) (*dialer, error) {
+	if len(conf.Resolve) == 0 && conf.DNS == (config.DNS{}) && len(conf.LocalAddresses) == 0 {
+		return nil, nil //nolint:nilnil
+	}
+
+	static, err := parseResolve(conf.Resolve)
+	if err != nil {
+		return nil, err
+	}
+
+	localIPs, err := parseLocalAddresses(conf.LocalAddresses)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &dialer{
+		resolver:       net.DefaultResolver,
+		static:         static,
+		cacheTTL:       conf.DNS.CacheTTL,
+		roundRobin:     conf.DNS.RoundRobin,
+		localIPs:       localIPs,
+		cache:          make(map[string]cachedHost),
+		next:           make(map[string]int),
+		quicTransports: make(map[string]*quic.Transport),
+	}
+
+	if conf.DNS.Server != "" {
+		server := conf.DNS.Server
+		if _, _, err = net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(server, defaultDNSPort)
+		}
+
+		d.resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				return d.net.DialContext(ctx, network, server)
+			},
+		}
+	}
+
+	return d, nil
+}
+
+func parseResolve(entries []string) (map[string][]string, error) {
+	static := make(map[string][]string, len(entries))
+
+	for _, entry := range entries {
+		// host:port:ip, where IPv6 addresses can be enclosed in brackets
+		parts := strings.SplitN(entry, ":", 3) //nolint:mnd
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("%w: %s", errInvalidResolve, entry)
+		}
+
+		var ips []string
+
+		for _, ip := range strings.Split(parts[2], ",") {
+			ip = strings.Trim(strings.TrimSpace(ip), "[]")
+			if net.ParseIP(ip) == nil {
+				return nil, fmt.Errorf("%w: %s", errInvalidResolve, entry)
+			}
+
+			ips = append(ips, ip)
+		}
+
+		static[net.JoinHostPort(strings.ToLower(parts[0]), parts[1])] = ips
+	}
+
+	return static, nil
+}
+
+// parseLocalAddresses expands interface names into their unicast IPs.
+func parseLocalAddresses(entries []string) ([]net.IP, error) {
+	var ips []net.IP
+
+	for _, entry := range entries {
+		if ip := net.ParseIP(entry); ip != nil {
+			ips = append(ips, ip)
+
+			continue
+		}
+
+		iface, err := net.InterfaceByName(entry)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errInvalidLocalAddress, entry)
+		}
+
+		addrs, err := iface.Addrs()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get addresses of interface %s: %w", entry, err)
+		}
+
+		found := false
+
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			// link-local addresses require a zone and are skipped
+			if ok && !ipNet.IP.IsLinkLocalUnicast() {
+				ips = append(ips, ipNet.IP)
+				found = true
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("%w: %s has no usable addresses", errInvalidLocalAddress, entry)
+		}
+	}
+
+	return ips, nil
+}
+
+// localIP returns the next local address of the same family as remote address, nil if binding is not configured.
+func (d *dialer) localIP(remote string) (net.IP, error) {
+	if len(d.localIPs) == 0 {
+		return nil, nil
+	}
+
+	host, _, err := net.SplitHostPort(remote)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address: %w", err)
+	}
+
+	isIPv4 := net.ParseIP(host).To4() != nil
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for i := range d.localIPs {
+		ip := d.localIPs[(d.nextLocal+i)%len(d.localIPs)]
+		if (ip.To4() != nil) == isIPv4 {
+			d.nextLocal += i + 1
+
+			return ip, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", errNoLocalAddress, remote)
+}
+
+func (d *dialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	addrs, err := d.resolve(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+
+	for _, resolved := range addrs {
+		conn, err := d.dial(ctx, network, resolved)
+		if err == nil {
+			return conn, nil
+		}
+
+		lastErr = err
+	}
+
+	return nil, lastErr //nolint:wrapcheck
+}
+
+func (d *dialer) dial(ctx context.Context, network, addr string) (net.Conn, error) {
+	localIP, err := d.localIP(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	netDialer := d.net
+	if localIP != nil {
+		netDialer.LocalAddr = &net.TCPAddr{IP: localIP}
+	}
+
+	return netDialer.DialContext(ctx, network, addr) //nolint:wrapcheck
+}
+
+// DialTLSContext is used by http2.Transport, which does not dial with DialContext.
+func (d *dialer) DialTLSContext(ctx context.Context, network, addr string, tlsConfig *tls.Config) (net.Conn, error) {
+	conn, err := d.DialContext(ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConn := tls.Client(conn, tlsConfig)
+	if err = tlsConn.HandshakeContext(ctx); err != nil {
+		conn.Close()
+
+		return nil, fmt.Errorf("tls handshake failed: %w", err)
+	}
+
+	return tlsConn, nil
+}
+
+// DialQUIC is used by http3.Transport.
+func (d *dialer) DialQUIC(
+	ctx context.Context,
+	addr string,
+	tlsConfig *tls.Config,
+	quicConfig *quic.Config,
+) (quic.EarlyConnection, error) {
+	addrs, err := d.resolve(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+
+	for _, resolved := range addrs {
+		conn, err := d.dialQUIC(ctx, resolved, tlsConfig, quicConfig)
+		if err == nil {
+			return conn, nil
+		}
+
+		lastErr = err
+	}
+
+	return nil, lastErr //nolint:wrapcheck
+}
+
+func (d *dialer) dialQUIC(
+	ctx context.Context,
+	addr string,
+	tlsConfig *tls.Config,
+	quicConfig *quic.Config,
+) (quic.EarlyConnection, error) {
+	localIP, err := d.localIP(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if localIP == nil {
+		return quic.DialAddrEarly(ctx, addr, tlsConfig, quicConfig) //nolint:wrapcheck
+	}
+
+	udpAddr, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address: %w", err)
+	}
+
+	transport, err := d.quicTransport(localIP)
+	if err != nil {
+		return nil, err
+	}
+
+	return transport.DialEarly(ctx, udpAddr, tlsConfig, quicConfig) //nolint:wrapcheck
+}
+
+// quicTransport returns a transport listening on the local address, it is shared by all connections from it.
+func (d *dialer) quicTransport(localIP net.IP) (*quic.Transport, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key := localIP.String()
+
+	if transport, ok := d.quicTransports[key]; ok {
+		return transport, nil
+	}
+
+	udpConn, err := net.ListenUDP("udp", &net.UDPAddr{IP: localIP})
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind to local address: %w", err)
+	}
+
+	transport := &quic.Transport{Conn: udpConn}
+	d.quicTransports[key] = transport
+
+	return transport, nil
+}
+
+// Close closes quic transports of local addresses and their sockets.
+func (d *dialer) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var errs []error
+
+	for key, transport := range d.quicTransports {
+		errs = append(errs, transport.Close())
+
+		// the socket is not closed by transport which did not create it
+		if err := transport.Conn.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+			errs = append(errs, err)
+		}
+
+		delete(d.quicTransports, key)
+	}
+
+	return errors.Join(errs...)
+}
+
+// resolve returns addresses to try in order, starting with the next one in round-robin mode.
+func (d *dialer) resolve(ctx context.Context, addr string) ([]string, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address: %w", err)
+	}
+
+	ips, err := d.lookup(ctx, strings.ToLower(host), port)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("%w: %s", errNoAddresses, host)
+	}
+
+	start := 0
+
+	if d.roundRobin {
+		d.mu.Lock()
+		start = d.next[addr] % len(ips)
+		d.next[addr] = start + 1
+		d.mu.Unlock()
+	}
+
+	addrs := make([]string, 0, len(ips))
+	for i := range ips {
+		addrs = append(addrs, net.JoinHostPort(ips[(start+i)%len(ips)], port))
+	}
+
+	return addrs, nil
+}
+
+func (d *dialer) lookup(ctx context.Context, host, port string) ([]string, error) {
+	if ips, ok := d.static[net.JoinHostPort(host, port)]; ok {
+		return ips, nil
+	}
+
+	if ips, ok := d.static[net.JoinHostPort(host, anyPort)]; ok {
+		return ips, nil
+	}
+
+	if net.ParseIP(host) != nil {
+		return []string{host}, nil
+	}
+
+	if d.cacheTTL > 0 {
+		d.mu.Lock()
+		cached, ok := d.cache[host]
+		d.mu.Unlock()
+
+		if ok && time.Now().Before(cached.expires) {
+			return cached.ips, nil
+		}
+	}
+
+	trace := httptrace.ContextClientTrace(ctx)
+	if trace != nil && trace.DNSStart != nil {
+		trace.DNSStart(httptrace.DNSStartInfo{Host: host})
+	}
+
+	ips, err := d.resolver.LookupHost(ctx, host)
+
+	if trace != nil && trace.DNSDone != nil {
+		trace.DNSDone(httptrace.DNSDoneInfo{Err: err})
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve host: %w", err)
+	}
+
+	if d.cacheTTL > 0 {
+		d.mu.Lock()
+		d.cache[host] = cachedHost{ips: ips, expires: time.Now().Add(d.cacheTTL)}
+		d.mu.Unlock()
+	}
+
+	return ips, nil
+}
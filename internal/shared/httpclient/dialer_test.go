@@ -0,0 +1,278 @@
+package httpclient_test
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/lameaux/bro/internal/shared/httpclient"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// newLoopbackServers starts servers on 127.0.0.1 and 127.0.0.2 sharing the same port.
+func newLoopbackServers(t *testing.T) (string, []*atomic.Int64) {
+	t.Helper()
+
+	first, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	port := strconv.Itoa(first.Addr().(*net.TCPAddr).Port) //nolint:forcetypeassert
+
+	second, err := net.Listen("tcp", "127.0.0.2:"+port)
+	if err != nil {
+		first.Close()
+		t.Skipf("127.0.0.2 is not available: %v", err)
+	}
+
+	hits := make([]*atomic.Int64, 0, 2) //nolint:mnd
+
+	for _, listener := range []net.Listener{first, second} {
+		counter := &atomic.Int64{}
+		hits = append(hits, counter)
+
+		server := &http.Server{ //nolint:gosec
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				counter.Add(1)
+				w.WriteHeader(http.StatusOK)
+			}),
+		}
+
+		go server.Serve(listener) //nolint:errcheck
+
+		t.Cleanup(func() { server.Close() })
+	}
+
+	return port, hits
+}
+
+// newDNSServer answers A queries for any name with 127.0.0.1 and 127.0.0.2.
+func newDNSServer(t *testing.T) (string, *atomic.Int64) {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	t.Cleanup(func() { conn.Close() })
+
+	queries := &atomic.Int64{}
+
+	go func() {
+		buf := make([]byte, 512) //nolint:mnd
+
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+
+			var msg dnsmessage.Message
+			if err = msg.Unpack(buf[:n]); err != nil || len(msg.Questions) == 0 {
+				continue
+			}
+
+			question := msg.Questions[0]
+			msg.Header.Response = true
+			msg.Answers = nil
+
+			if question.Type == dnsmessage.TypeA {
+				queries.Add(1)
+
+				for _, ip := range [][4]byte{{127, 0, 0, 1}, {127, 0, 0, 2}} {
+					msg.Answers = append(msg.Answers, dnsmessage.Resource{
+						Header: dnsmessage.ResourceHeader{
+							Name: question.Name, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET, TTL: 60,
+						},
+						Body: &dnsmessage.AResource{A: ip},
+					})
+				}
+			}
+
+			packed, err := msg.Pack()
+			if err != nil {
+				continue
+			}
+
+			_, _ = conn.WriteTo(packed, addr)
+		}
+	}()
+
+	return conn.LocalAddr().String(), queries
+}
+
+func TestNewResolve(t *testing.T) {
+	t.Parallel()
+
+	port, hits := newLoopbackServers(t)
+
+	tests := []struct {
+		name string
+		conf config.HTTPClient
+		hits []int64
+	}{
+		{
+			name: "static",
+			conf: config.HTTPClient{Resolve: []string{"bro.test:" + port + ":127.0.0.2"}},
+			hits: []int64{0, 4},
+		},
+		{
+			name: "static any port",
+			conf: config.HTTPClient{Resolve: []string{"bro.test:*:127.0.0.1"}},
+			hits: []int64{4, 0},
+		},
+		{
+			name: "static round robin",
+			conf: config.HTTPClient{
+				Resolve: []string{"bro.test:" + port + ":127.0.0.1,127.0.0.2"},
+				DNS:     config.DNS{RoundRobin: true},
+			},
+			hits: []int64{2, 2},
+		},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			before := []int64{hits[0].Load(), hits[1].Load()}
+
+			tt.conf.DisableKeepAlive = true
+
+			client, err := httpclient.New(tt.conf)
+			if err != nil {
+				t.Fatalf("failed to create client: %v", err)
+			}
+
+			for range 4 {
+				if err = get(client, "http://bro.test:"+port); err != nil {
+					t.Fatalf("request failed: %v", err)
+				}
+			}
+
+			for i := range hits {
+				if got := hits[i].Load() - before[i]; got != tt.hits[i] {
+					t.Errorf("server %d got %d hits, want %d", i, got, tt.hits[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNewDNSServer(t *testing.T) {
+	t.Parallel()
+
+	port, hits := newLoopbackServers(t)
+	dnsAddr, queries := newDNSServer(t)
+
+	client, err := httpclient.New(config.HTTPClient{
+		DisableKeepAlive: true,
+		DNS: config.DNS{
+			Server:     dnsAddr,
+			CacheTTL:   time.Minute,
+			RoundRobin: true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	for range 4 {
+		if err = get(client, "http://bro.test:"+port); err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+	}
+
+	if got := queries.Load(); got != 1 {
+		t.Errorf("got %d dns queries, want 1", got)
+	}
+
+	for i := range hits {
+		if got := hits[i].Load(); got != 2 {
+			t.Errorf("server %d got %d hits, want 2", i, got)
+		}
+	}
+}
+
+func TestNewInvalidResolve(t *testing.T) {
+	t.Parallel()
+
+	for _, entry := range []string{"bro.test", "bro.test:80", "bro.test:80:not-an-ip", ":80:127.0.0.1"} {
+		if _, err := httpclient.New(config.HTTPClient{Resolve: []string{entry}}); err == nil {
+			t.Errorf("expected error for resolve entry %s", entry)
+		}
+	}
+}
+
+func TestNewLocalAddresses(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu      sync.Mutex
+		sources = make(map[string]int)
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, _ := net.SplitHostPort(r.RemoteAddr)
+
+		mu.Lock()
+		sources[host]++
+		mu.Unlock()
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := httpclient.New(config.HTTPClient{
+		DisableKeepAlive: true,
+		LocalAddresses:   []string{"::1", "127.0.0.2", "127.0.0.3"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	for range 4 {
+		if err = get(client, server.URL); err != nil {
+			t.Skipf("binding to loopback addresses is not available: %v", err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if sources["127.0.0.2"] != 2 || sources["127.0.0.3"] != 2 {
+		t.Errorf("got sources %v, want 2 requests from each local address", sources)
+	}
+}
+
+func TestNewLocalAddressesFamily(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := httpclient.New(config.HTTPClient{LocalAddresses: []string{"::1"}})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	if err = get(client, server.URL); err == nil {
+		t.Error("expected error for IPv6 local address and IPv4 target")
+	}
+}
+
+func TestNewInvalidLocalAddress(t *testing.T) {
+	t.Parallel()
+
+	if _, err := httpclient.New(config.HTTPClient{LocalAddresses: []string{"no-such-interface0"}}); err == nil {
+		t.Error("expected error for unknown interface")
+	}
+}
@@ -1,7 +1,9 @@
 package httpclient
 
 import (
+	"fmt"
 	"net/http"
+	"net/url"
 
 	"github.com/lameaux/bro/internal/client/config"
 	"github.com/rs/zerolog/log"
@@ -9,24 +11,33 @@ import (
 
 const defaultMaxIdleConnsPerHost = 100
 
-func New(conf config.HTTPClient) *http.Client {
-	maxIdleConnsPerHost := defaultMaxIdleConnsPerHost
-	if conf.MaxIdleConnsPerHost > 0 {
-		maxIdleConnsPerHost = conf.MaxIdleConnsPerHost
-	}
-
+func New(conf config.HTTPClient) (*http.Client, error) {
 	log.Debug().
 		Bool("disableKeepAlive", conf.DisableKeepAlive).
 		Dur("timeout", conf.Timeout).
-		Int("maxIdleConnsPerHost", maxIdleConnsPerHost).
+		Int("maxIdleConnsPerHost", conf.MaxIdleConnsPerHost).
+		Int("maxConnsPerHost", conf.MaxConnsPerHost).
+		Dur("idleConnTimeout", conf.IdleConnTimeout).
+		Str("connectionStrategy", conf.ConnectionStrategy).
+		Int("requestsPerConnection", conf.RequestsPerConnection).
+		Str("cookies", conf.Cookies).
+		Str("protocol", conf.Protocol).
+		Str("proxy", redactedURL(conf.Proxy.URL)).
+		Bool("proxyFromEnvironment", conf.Proxy.FromEnvironment).
+		Bool("tlsInsecureSkipVerify", conf.TLS.InsecureSkipVerify).
+		Strs("resolve", conf.Resolve).
+		Str("dnsServer", conf.DNS.Server).
+		Strs("localAddresses", conf.LocalAddresses).
 		Msg("creating http client")
 
-	// TODO: http2 support
-	// TODO: proxy
+	tlsConfig, err := NewTLSConfig(conf.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tls config: %w", err)
+	}
 
-	transport := &http.Transport{
-		MaxIdleConnsPerHost: maxIdleConnsPerHost,
-		DisableKeepAlives:   conf.DisableKeepAlive,
+	transport, err := newTransport(conf, tlsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transport: %w", err)
 	}
 
 	client := &http.Client{
@@ -40,5 +51,14 @@ func New(conf config.HTTPClient) *http.Client {
 		}
 	}
 
-	return client
+	return client, nil
+}
+
+func redactedURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+
+	return u.Redacted()
 }
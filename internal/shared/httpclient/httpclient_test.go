@@ -0,0 +1,94 @@
+package httpclient_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/lameaux/bro/internal/shared/httpclient"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+func TestNewProtocols(t *testing.T) {
+	t.Parallel()
+
+	handler := h2c.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), &http2.Server{})
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		name       string
+		conf       config.HTTPClient
+		protoMajor int
+	}{
+		{name: "auto", conf: config.HTTPClient{}, protoMajor: 1},
+		{name: "http1", conf: config.HTTPClient{Protocol: httpclient.ProtocolHTTP1}, protoMajor: 1},
+		{name: "h2c", conf: config.HTTPClient{Protocol: httpclient.ProtocolH2C}, protoMajor: 2},
+		{
+			name: "h2c pool",
+			conf: config.HTTPClient{
+				Protocol: httpclient.ProtocolH2C,
+				HTTP2:    config.HTTP2{MaxConcurrentStreams: 1, Connections: 2},
+			},
+			protoMajor: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, err := httpclient.New(tt.conf)
+			if err != nil {
+				t.Fatalf("failed to create client: %v", err)
+			}
+
+			for range 3 {
+				req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+				if err != nil {
+					t.Fatalf("failed to create request: %v", err)
+				}
+
+				resp, err := client.Do(req)
+				if err != nil {
+					t.Fatalf("request failed: %v", err)
+				}
+
+				resp.Body.Close()
+
+				if resp.ProtoMajor != tt.protoMajor {
+					t.Errorf("got protocol %s, want major version %d", resp.Proto, tt.protoMajor)
+				}
+			}
+		})
+	}
+}
+
+func TestNewHTTP1OnlySettings(t *testing.T) {
+	t.Parallel()
+
+	for _, protocol := range []string{httpclient.ProtocolHTTP2, httpclient.ProtocolH2C, httpclient.ProtocolHTTP3} {
+		for _, conf := range []config.HTTPClient{
+			{Protocol: protocol, MaxConnsPerHost: 10},
+			{Protocol: protocol, DisableKeepAlive: true},
+		} {
+			if _, err := httpclient.New(conf); err == nil {
+				t.Errorf("expected error for %+v", conf)
+			}
+		}
+	}
+}
+
+func TestNewUnknownProtocol(t *testing.T) {
+	t.Parallel()
+
+	if _, err := httpclient.New(config.HTTPClient{Protocol: "spdy"}); err == nil {
+		t.Error("expected error for unknown protocol")
+	}
+}
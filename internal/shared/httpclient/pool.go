@@ -0,0 +1,97 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// connPool spreads requests over several transports, each holding its own connection,
+// and limits number of concurrent streams per transport.
+type connPool struct {
+	transports []http.RoundTripper
+	slots      []chan struct{}
+	next       atomic.Uint64
+}
+
+func newConnPool(transports []http.RoundTripper, maxConcurrentStreams int) *connPool {
+	pool := &connPool{
+		transports: transports,
+	}
+
+	if maxConcurrentStreams > 0 {
+		pool.slots = make([]chan struct{}, len(transports))
+		for i := range pool.slots {
+			pool.slots[i] = make(chan struct{}, maxConcurrentStreams)
+		}
+	}
+
+	return pool
+}
+
+func (p *connPool) RoundTrip(req *http.Request) (*http.Response, error) {
+	idx := int(p.next.Add(1) % uint64(len(p.transports)))
+
+	release := func() {}
+
+	if p.slots != nil {
+		slots := p.slots[idx]
+
+		select {
+		case slots <- struct{}{}:
+		case <-req.Context().Done():
+			return nil, fmt.Errorf("failed to acquire stream: %w", req.Context().Err())
+		}
+
+		var once sync.Once
+		release = func() {
+			once.Do(func() { <-slots })
+		}
+	}
+
+	resp, err := p.transports[idx].RoundTrip(req)
+	if err != nil {
+		release()
+
+		return nil, err //nolint:wrapcheck
+	}
+
+	// stream is finished when response body is closed
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+
+	return resp, nil
+}
+
+func (p *connPool) CloseIdleConnections() {
+	for _, transport := range p.transports {
+		if t, ok := transport.(closeIdler); ok {
+			t.CloseIdleConnections()
+		}
+	}
+}
+
+// Close closes transports of the pool.
+func (p *connPool) Close() error {
+	errs := make([]error, 0, len(p.transports))
+
+	for _, transport := range p.transports {
+		errs = append(errs, closeTransport(transport))
+	}
+
+	return errors.Join(errs...)
+}
+
+type releasingBody struct {
+	io.ReadCloser
+
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	defer b.release()
+
+	return b.ReadCloser.Close() //nolint:wrapcheck
+}
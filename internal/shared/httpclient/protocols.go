@@ -0,0 +1,224 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/quic-go/quic-go/http3"
+	"golang.org/x/net/http2"
+)
+
+const (
+	ProtocolAuto  = ""
+	ProtocolHTTP1 = "http1"
+	ProtocolHTTP2 = "http2"
+	ProtocolH2C   = "h2c"
+	ProtocolHTTP3 = "http3"
+)
+
+var (
+	errUnknownProtocol  = errors.New("unknown protocol")
+	errHTTP1OnlySetting = errors.New("maxConnsPerHost and disableKeepAlive are supported only by http1, use http2.connections")
+)
+
+// transportOptions are shared by all transports of a client.
+type transportOptions struct {
+	tlsConfig *tls.Config
+	proxy     proxyFunc
+	dialer    *dialer
+}
+
+func (o transportOptions) dialContext() dialFunc {
+	if o.dialer != nil {
+		return o.dialer.DialContext
+	}
+
+	return (&net.Dialer{}).DialContext
+}
+
+func newTransport(conf config.HTTPClient, tlsConfig *tls.Config) (http.RoundTripper, error) { //nolint:ireturn
+	if err := validateUnixSocket(&conf); err != nil {
+		return nil, err
+	}
+
+	if err := validateHTTP1Settings(&conf); err != nil {
+		return nil, err
+	}
+
+	proxy, err := newProxyFunc(conf.Proxy)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer, err := newDialer(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := transportOptions{
+		tlsConfig: tlsConfig,
+		proxy:     proxy,
+		dialer:    dialer,
+	}
+
+	connections := max(conf.HTTP2.Connections, 1)
+	transports := make([]http.RoundTripper, 0, connections)
+
+	for range connections {
+		transport, err := newProtocolTransport(conf, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		transports = append(transports, transport)
+	}
+
+	var transport http.RoundTripper = transports[0]
+	if len(transports) > 1 || conf.HTTP2.MaxConcurrentStreams > 0 {
+		transport = newConnPool(transports, conf.HTTP2.MaxConcurrentStreams)
+	}
+
+	if dialer != nil {
+		return &dialerTransport{RoundTripper: transport, dialer: dialer}, nil
+	}
+
+	return transport, nil
+}
+
+// dialerTransport closes the dialer with the transport, as it keeps sockets of local addresses.
+type dialerTransport struct {
+	http.RoundTripper
+
+	dialer *dialer
+}
+
+func (t *dialerTransport) CloseIdleConnections() {
+	if closer, ok := t.RoundTripper.(closeIdler); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
+func (t *dialerTransport) Close() error {
+	return errors.Join(closeTransport(t.RoundTripper), t.dialer.Close())
+}
+
+type closeIdler interface {
+	CloseIdleConnections()
+}
+
+// closeTransport closes a transport which holds resources, e.g. http3, or its idle connections.
+func closeTransport(transport http.RoundTripper) error {
+	switch t := transport.(type) {
+	case io.Closer:
+		return t.Close() //nolint:wrapcheck
+	case closeIdler:
+		t.CloseIdleConnections()
+	}
+
+	return nil
+}
+
+func newProtocolTransport(conf config.HTTPClient, opts transportOptions) (http.RoundTripper, error) { //nolint:ireturn
+	if opts.proxy != nil && conf.Protocol != ProtocolAuto && conf.Protocol != ProtocolHTTP1 {
+		return nil, fmt.Errorf("%w: %s", errProxyNotSupported, conf.Protocol)
+	}
+
+	switch conf.Protocol {
+	case ProtocolAuto:
+		transport := newHTTPTransport(conf, opts)
+		transport.ForceAttemptHTTP2 = true
+
+		return transport, nil
+	case ProtocolHTTP1:
+		transport := newHTTPTransport(conf, opts)
+		// non-nil empty map disables HTTP/2
+		transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+
+		return transport, nil
+	case ProtocolHTTP2:
+		transport := &http2.Transport{
+			TLSClientConfig:            opts.tlsConfig,
+			IdleConnTimeout:            conf.IdleConnTimeout,
+			DisableCompression:         true,
+			StrictMaxConcurrentStreams: conf.HTTP2.MaxConcurrentStreams > 0,
+		}
+
+		if opts.dialer != nil {
+			transport.DialTLSContext = opts.dialer.DialTLSContext
+		}
+
+		return transport, nil
+	case ProtocolH2C:
+		dialContext := opts.dialContext()
+
+		return &http2.Transport{
+			AllowHTTP:                  true,
+			IdleConnTimeout:            conf.IdleConnTimeout,
+			DisableCompression:         true,
+			StrictMaxConcurrentStreams: conf.HTTP2.MaxConcurrentStreams > 0,
+			DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+				return dialContext(ctx, network, addr)
+			},
+		}, nil
+	case ProtocolHTTP3:
+		transport := &http3.Transport{
+			TLSClientConfig:    opts.tlsConfig,
+			DisableCompression: true,
+		}
+
+		if opts.dialer != nil {
+			transport.Dial = opts.dialer.DialQUIC
+		}
+
+		return transport, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", errUnknownProtocol, conf.Protocol)
+}
+
+// validateHTTP1Settings rejects settings of http.Transport which are ignored by http2 and http3 transports.
+func validateHTTP1Settings(conf *config.HTTPClient) error {
+	switch conf.Protocol {
+	case ProtocolAuto, ProtocolHTTP1:
+		return nil
+	}
+
+	if conf.MaxConnsPerHost > 0 || conf.DisableKeepAlive {
+		return fmt.Errorf("%w: %s", errHTTP1OnlySetting, conf.Protocol)
+	}
+
+	return nil
+}
+
+func newHTTPTransport(conf config.HTTPClient, opts transportOptions) *http.Transport {
+	maxIdleConnsPerHost := defaultMaxIdleConnsPerHost
+	if conf.MaxIdleConnsPerHost > 0 {
+		maxIdleConnsPerHost = conf.MaxIdleConnsPerHost
+	}
+
+	transport := &http.Transport{
+		Proxy:               opts.proxy,
+		TLSClientConfig:     opts.tlsConfig,
+		MaxIdleConnsPerHost: maxIdleConnsPerHost,
+		MaxConnsPerHost:     conf.MaxConnsPerHost,
+		IdleConnTimeout:     conf.IdleConnTimeout,
+		DisableKeepAlives:   conf.DisableKeepAlive,
+		// response is decompressed by runner to count bytes on the wire
+		DisableCompression: true,
+	}
+
+	transport.DialContext = opts.dialContext()
+
+	if conf.UnixSocket != "" {
+		transport.DialContext = unixSocketDial(conf.UnixSocket)
+		transport.Proxy = nil
+	}
+
+	return transport
+}
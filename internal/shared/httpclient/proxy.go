@@ -0,0 +1,147 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/lameaux/bro/internal/client/config"
+	"golang.org/x/net/http/httpproxy"
+)
+
+type proxyFunc func(req *http.Request) (*url.URL, error)
+
+var (
+	errInvalidProxy        = errors.New("invalid proxy")
+	errProxyNotSupported   = errors.New("proxy is not supported")
+	supportedProxySchemes  = []string{"http", "https", "socks5", "socks5h"}  //nolint:gochecknoglobals
+	defaultPortsForSchemes = map[string]string{"http": "80", "https": "443"} //nolint:gochecknoglobals
+)
+
+func newProxyFunc(conf config.Proxy) (proxyFunc, error) {
+	if conf.URL == "" {
+		if conf.FromEnvironment {
+			envProxy := httpproxy.FromEnvironment().ProxyFunc()
+
+			return func(req *http.Request) (*url.URL, error) {
+				return envProxy(req.URL)
+			}, nil
+		}
+
+		return nil, nil //nolint:nilnil
+	}
+
+	proxyURL, err := url.Parse(conf.URL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidProxy, err)
+	}
+
+	if !isSupportedProxyScheme(proxyURL.Scheme) || proxyURL.Host == "" {
+		return nil, fmt.Errorf("%w: %s", errInvalidProxy, proxyURL.Redacted())
+	}
+
+	noProxy := newNoProxyMatcher(conf.NoProxy)
+
+	return func(req *http.Request) (*url.URL, error) {
+		if noProxy.match(req.URL) {
+			return nil, nil //nolint:nilnil
+		}
+
+		return proxyURL, nil
+	}, nil
+}
+
+func isSupportedProxyScheme(scheme string) bool {
+	for _, supported := range supportedProxySchemes {
+		if scheme == supported {
+			return true
+		}
+	}
+
+	return false
+}
+
+type noProxyMatcher struct {
+	all     bool
+	ips     []net.IP
+	cidrs   []*net.IPNet
+	domains []string // with optional :port
+}
+
+func newNoProxyMatcher(entries []string) *noProxyMatcher {
+	matcher := &noProxyMatcher{}
+
+	for _, entry := range entries {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+
+		switch {
+		case entry == "":
+			continue
+		case entry == "*":
+			matcher.all = true
+		default:
+			if _, cidr, err := net.ParseCIDR(entry); err == nil {
+				matcher.cidrs = append(matcher.cidrs, cidr)
+
+				continue
+			}
+
+			if ip := net.ParseIP(entry); ip != nil {
+				matcher.ips = append(matcher.ips, ip)
+
+				continue
+			}
+
+			matcher.domains = append(matcher.domains, strings.TrimPrefix(entry, "."))
+		}
+	}
+
+	return matcher
+}
+
+func (m *noProxyMatcher) match(target *url.URL) bool {
+	if m.all {
+		return true
+	}
+
+	host := strings.ToLower(target.Hostname())
+
+	port := target.Port()
+	if port == "" {
+		port = defaultPortsForSchemes[target.Scheme]
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		for _, noProxyIP := range m.ips {
+			if noProxyIP.Equal(ip) {
+				return true
+			}
+		}
+
+		for _, cidr := range m.cidrs {
+			if cidr.Contains(ip) {
+				return true
+			}
+		}
+	}
+
+	for _, domain := range m.domains {
+		domainHost, domainPort, err := net.SplitHostPort(domain)
+		if err != nil {
+			domainHost, domainPort = domain, ""
+		}
+
+		if domainPort != "" && domainPort != port {
+			continue
+		}
+
+		if host == domainHost || strings.HasSuffix(host, "."+domainHost) {
+			return true
+		}
+	}
+
+	return false
+}
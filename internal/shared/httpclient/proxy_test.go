@@ -0,0 +1,103 @@
+package httpclient_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/lameaux/bro/internal/shared/httpclient"
+)
+
+func TestNewProxy(t *testing.T) {
+	t.Parallel()
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(target.Close)
+
+	var proxied atomic.Int64
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied.Add(1)
+
+		if r.Header.Get("Proxy-Authorization") == "" {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(proxy.Close)
+
+	proxyURL := "http://user:secret@" + proxy.Listener.Addr().String()
+
+	tests := []struct {
+		name    string
+		proxy   config.Proxy
+		code    int
+		proxied int64
+	}{
+		{name: "proxy", proxy: config.Proxy{URL: proxyURL}, code: http.StatusAccepted, proxied: 1},
+		{name: "noProxy ip", proxy: config.Proxy{URL: proxyURL, NoProxy: []string{"127.0.0.1"}}, code: http.StatusOK},
+		{name: "noProxy cidr", proxy: config.Proxy{URL: proxyURL, NoProxy: []string{"127.0.0.0/8"}}, code: http.StatusOK},
+		{name: "noProxy all", proxy: config.Proxy{URL: proxyURL, NoProxy: []string{"*"}}, code: http.StatusOK},
+		{
+			name:    "noProxy other host",
+			proxy:   config.Proxy{URL: proxyURL, NoProxy: []string{"example.com"}},
+			code:    http.StatusAccepted,
+			proxied: 1,
+		},
+	}
+
+	for _, tt := range tests { //nolint:paralleltest
+		t.Run(tt.name, func(t *testing.T) {
+			before := proxied.Load()
+
+			client, err := httpclient.New(config.HTTPClient{Proxy: tt.proxy})
+			if err != nil {
+				t.Fatalf("failed to create client: %v", err)
+			}
+
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, target.URL, nil)
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.code {
+				t.Errorf("got code %d, want %d", resp.StatusCode, tt.code)
+			}
+
+			if got := proxied.Load() - before; got != tt.proxied {
+				t.Errorf("got %d proxied requests, want %d", got, tt.proxied)
+			}
+		})
+	}
+}
+
+func TestNewInvalidProxy(t *testing.T) {
+	t.Parallel()
+
+	tests := []config.HTTPClient{
+		{Proxy: config.Proxy{URL: "ftp://proxy:21"}},
+		{Proxy: config.Proxy{URL: "localhost"}},
+		{Proxy: config.Proxy{URL: "socks5://localhost:1080"}, Protocol: httpclient.ProtocolHTTP3},
+	}
+
+	for _, conf := range tests {
+		if _, err := httpclient.New(conf); err == nil {
+			t.Errorf("expected error for proxy %s with protocol %q", conf.Proxy.URL, conf.Protocol)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package httpclient
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/lameaux/bro/internal/client/config"
+)
+
+func TestClientsClose_QUICTransports(t *testing.T) {
+	t.Parallel()
+
+	clients, err := NewClients(config.HTTPClient{Protocol: ProtocolHTTP3, LocalAddresses: []string{"127.0.0.1"}})
+	if err != nil {
+		t.Fatalf("failed to create clients: %v", err)
+	}
+
+	transport, _ := clients.Client(0).Transport.(*dialerTransport)
+	if transport == nil {
+		t.Fatalf("expected transport with dialer, got %T", clients.Client(0).Transport)
+	}
+
+	quicTransport, err := transport.dialer.quicTransport(net.ParseIP("127.0.0.1"))
+	if err != nil {
+		t.Skipf("binding to loopback address is not available: %v", err)
+	}
+
+	if err = clients.Close(); err != nil {
+		t.Fatalf("failed to close clients: %v", err)
+	}
+
+	if _, err = quicTransport.Conn.WriteTo([]byte("ping"), quicTransport.Conn.LocalAddr()); !errors.Is(err, net.ErrClosed) {
+		t.Errorf("expected closed socket, got %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/lameaux/bro/internal/client/config"
+)
+
+const sessionCacheSize = 1024
+
+var (
+	errInvalidCA          = errors.New("no certificates found in ca file")
+	errInvalidTLSVersion  = errors.New("invalid tls version")
+	errInvalidCipherSuite = errors.New("invalid cipher suite")
+	errMissingKeyPair     = errors.New("both certFile and keyFile must be set")
+)
+
+//nolint:gochecknoglobals
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// NewTLSConfig builds client tls settings, it is shared with grpc requests.
+func NewTLSConfig(conf config.TLS) (*tls.Config, error) {
+	tlsConfig := &tls.Config{ //nolint:gosec
+		InsecureSkipVerify: conf.InsecureSkipVerify,
+		ServerName:         conf.ServerName,
+	}
+
+	if conf.DisableSessionResumption {
+		tlsConfig.SessionTicketsDisabled = true
+	} else {
+		tlsConfig.ClientSessionCache = tls.NewLRUClientSessionCache(sessionCacheSize)
+	}
+
+	if conf.CAFile != "" {
+		pool, err := loadCAFile(conf.CAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if conf.CertFile != "" || conf.KeyFile != "" {
+		if conf.CertFile == "" || conf.KeyFile == "" {
+			return nil, errMissingKeyPair
+		}
+
+		cert, err := tls.LoadX509KeyPair(conf.CertFile, conf.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	var err error
+
+	if tlsConfig.MinVersion, err = parseTLSVersion(conf.MinVersion); err != nil {
+		return nil, err
+	}
+
+	if tlsConfig.MaxVersion, err = parseTLSVersion(conf.MaxVersion); err != nil {
+		return nil, err
+	}
+
+	if tlsConfig.CipherSuites, err = parseCipherSuites(conf.CipherSuites); err != nil {
+		return nil, err
+	}
+
+	return tlsConfig, nil
+}
+
+func loadCAFile(fileName string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ca file: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("%w: %s", errInvalidCA, fileName)
+	}
+
+	return pool, nil
+}
+
+func parseTLSVersion(version string) (uint16, error) {
+	if version == "" {
+		return 0, nil
+	}
+
+	value, ok := tlsVersions[version]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", errInvalidTLSVersion, version)
+	}
+
+	return value, nil
+}
+
+func parseCipherSuites(names []string) ([]uint16, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	known := make(map[string]uint16)
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		known[suite.Name] = suite.ID
+	}
+
+	ids := make([]uint16, 0, len(names))
+
+	for _, name := range names {
+		id, ok := known[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", errInvalidCipherSuite, name)
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
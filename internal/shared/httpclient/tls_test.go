@@ -0,0 +1,205 @@
+package httpclient_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/lameaux/bro/internal/shared/httpclient"
+)
+
+type testCert struct {
+	certFile string
+	keyFile  string
+	cert     tls.Certificate
+	pool     *x509.CertPool
+}
+
+// newTestCert generates a self-signed certificate valid for 127.0.0.1 for both server and client auth.
+func newTestCert(t *testing.T) *testCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "bro test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		DNSNames:              []string{"bro.test"},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+
+	dir := t.TempDir()
+	result := &testCert{
+		certFile: filepath.Join(dir, "cert.pem"),
+		keyFile:  filepath.Join(dir, "key.pem"),
+		pool:     x509.NewCertPool(),
+	}
+
+	if err = os.WriteFile(result.certFile, certPEM, 0o600); err != nil {
+		t.Fatalf("failed to write cert: %v", err)
+	}
+
+	if err = os.WriteFile(result.keyFile, keyPEM, 0o600); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+
+	if result.cert, err = tls.X509KeyPair(certPEM, keyPEM); err != nil {
+		t.Fatalf("failed to load key pair: %v", err)
+	}
+
+	result.pool.AppendCertsFromPEM(certPEM)
+
+	return result
+}
+
+func newTLSServer(t *testing.T, tlsConfig *tls.Config) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = tlsConfig
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func get(client *http.Client, url string) error {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return resp.Body.Close() //nolint:wrapcheck
+}
+
+func TestNewTLS(t *testing.T) {
+	t.Parallel()
+
+	cert := newTestCert(t)
+
+	server := newTLSServer(t, &tls.Config{ //nolint:gosec
+		Certificates: []tls.Certificate{cert.cert},
+	})
+
+	mtlsServer := newTLSServer(t, &tls.Config{
+		Certificates: []tls.Certificate{cert.cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    cert.pool,
+		MinVersion:   tls.VersionTLS12,
+	})
+
+	tls12Server := newTLSServer(t, &tls.Config{
+		Certificates: []tls.Certificate{cert.cert},
+		MaxVersion:   tls.VersionTLS12,
+	})
+
+	tests := []struct {
+		name    string
+		url     string
+		tls     config.TLS
+		wantErr bool
+	}{
+		{name: "unknown ca", url: server.URL, wantErr: true},
+		{name: "ca file", url: server.URL, tls: config.TLS{CAFile: cert.certFile}},
+		{name: "insecure", url: server.URL, tls: config.TLS{InsecureSkipVerify: true}},
+		{name: "server name", url: server.URL, tls: config.TLS{CAFile: cert.certFile, ServerName: "bro.test"}},
+		{
+			name:    "server name mismatch",
+			url:     server.URL,
+			tls:     config.TLS{CAFile: cert.certFile, ServerName: "other.test"},
+			wantErr: true,
+		},
+		{name: "mtls without cert", url: mtlsServer.URL, tls: config.TLS{CAFile: cert.certFile}, wantErr: true},
+		{
+			name: "mtls",
+			url:  mtlsServer.URL,
+			tls:  config.TLS{CAFile: cert.certFile, CertFile: cert.certFile, KeyFile: cert.keyFile},
+		},
+		{name: "min version", url: tls12Server.URL, tls: config.TLS{CAFile: cert.certFile, MinVersion: "1.3"}, wantErr: true},
+		{
+			name: "cipher suites",
+			url:  tls12Server.URL,
+			tls: config.TLS{
+				CAFile:       cert.certFile,
+				MaxVersion:   "1.2",
+				CipherSuites: []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, err := httpclient.New(config.HTTPClient{TLS: tt.tls})
+			if err != nil {
+				t.Fatalf("failed to create client: %v", err)
+			}
+
+			err = get(client, tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewInvalidTLS(t *testing.T) {
+	t.Parallel()
+
+	tests := []config.TLS{
+		{CAFile: "missing.pem"},
+		{CertFile: "cert.pem"},
+		{MinVersion: "2.0"},
+		{CipherSuites: []string{"TLS_UNKNOWN"}},
+	}
+
+	for _, conf := range tests {
+		if _, err := httpclient.New(config.HTTPClient{TLS: conf}); err == nil {
+			t.Errorf("expected error for tls config %+v", conf)
+		}
+	}
+}
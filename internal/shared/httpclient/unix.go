@@ -0,0 +1,37 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/lameaux/bro/internal/client/config"
+)
+
+var errUnixSocketProtocol = errors.New("unix socket is supported only by http1")
+
+type dialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// unixSocketDial dials the socket for any address, URL host is kept for Host header and TLS server name.
+func unixSocketDial(socket string) dialFunc {
+	return func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var netDialer net.Dialer
+
+		return netDialer.DialContext(ctx, "unix", socket) //nolint:wrapcheck
+	}
+}
+
+// validateUnixSocket rejects protocols whose transports do not dial unix sockets.
+func validateUnixSocket(conf *config.HTTPClient) error {
+	if conf.UnixSocket == "" {
+		return nil
+	}
+
+	switch conf.Protocol {
+	case ProtocolAuto, ProtocolHTTP1:
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", errUnixSocketProtocol, conf.Protocol)
+}
@@ -0,0 +1,71 @@
+package httpclient_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/lameaux/bro/internal/client/config"
+	"github.com/lameaux/bro/internal/shared/httpclient"
+)
+
+func TestUnixSocket(t *testing.T) {
+	t.Parallel()
+
+	socket := filepath.Join(t.TempDir(), "bro.sock")
+
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("unix sockets are not available: %v", err)
+	}
+
+	server := &http.Server{ //nolint:gosec
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Host", r.Host)
+			w.WriteHeader(http.StatusOK)
+		}),
+	}
+
+	go server.Serve(listener) //nolint:errcheck
+
+	t.Cleanup(func() { server.Close() })
+
+	for _, protocol := range []string{httpclient.ProtocolAuto, httpclient.ProtocolHTTP1} {
+		client, err := httpclient.New(config.HTTPClient{
+			Protocol:   protocol,
+			Proxy:      config.Proxy{URL: "http://127.0.0.1:1"},
+			UnixSocket: socket,
+		})
+		if err != nil {
+			t.Fatalf("failed to create client: %v", err)
+		}
+
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://sidecar/health", nil)
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+
+		resp.Body.Close()
+
+		if host := resp.Header.Get("X-Host"); host != "sidecar" {
+			t.Errorf("got host %s, want sidecar", host)
+		}
+	}
+}
+
+func TestUnixSocketProtocol(t *testing.T) {
+	t.Parallel()
+
+	for _, protocol := range []string{httpclient.ProtocolHTTP2, httpclient.ProtocolH2C, httpclient.ProtocolHTTP3} {
+		if _, err := httpclient.New(config.HTTPClient{Protocol: protocol, UnixSocket: "/tmp/bro.sock"}); err == nil {
+			t.Errorf("expected error for unix socket with %s", protocol)
+		}
+	}
+}
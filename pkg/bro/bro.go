@@ -0,0 +1,41 @@
+// Package bro runs load tests from Go code, e.g. next to unit tests:
+//
+//	result, err := bro.Run(ctx, conf)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//
+//	bro.RequireThresholds(t, result)
+//
+// Logs are written with zerolog global logger, use zerolog.SetGlobalLevel to change their level.
+package bro
+
+import (
+	"context"
+
+	"github.com/lameaux/bro/internal/client/engine"
+	"github.com/lameaux/bro/internal/client/report"
+)
+
+// Result is a summary of a run, the same as JSON output of bro.
+type (
+	Result         = report.Report
+	ScenarioResult = report.Scenario
+)
+
+// Run executes scenarios of a config until they finish or ctx is canceled,
+// listeners are notified about requests in addition to counters of the result.
+// Defaults of a config are applied by LoadConfig, Run uses scenarios as they are.
+// A result is returned with an error of scenarios which failed to start, it is not successful then
+// and lists them as missing.
+func Run(ctx context.Context, conf *Config, listeners ...StatListener) (*Result, error) {
+	e, err := engine.New(conf, listeners)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	defer e.Close()
+
+	results, err := e.Run(ctx)
+
+	return report.New(conf, results, err == nil && results.AllThresholdsPassed()), err //nolint:wrapcheck
+}
@@ -0,0 +1,158 @@
+package bro_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lameaux/bro/pkg/bro"
+)
+
+type countingListener struct {
+	responses atomic.Int64
+	failed    atomic.Int64
+}
+
+func (l *countingListener) TrackFailed(*bro.RequestInfo, error) {
+	l.failed.Add(1)
+}
+
+func (l *countingListener) TrackResponse(*bro.RequestInfo, bool, time.Duration) {
+	l.responses.Add(1)
+}
+
+// fakeT records a failure instead of stopping the test.
+type fakeT struct {
+	testing.TB
+	failed  bool
+	message string
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Fatalf(format string, args ...any) {
+	t.failed = true
+	t.message = fmt.Sprintf(format, args...)
+}
+
+func newConfig(url string, minRate float64) *bro.Config {
+	return &bro.Config{
+		Name: "library",
+		Scenarios: []*bro.Scenario{
+			{
+				Name:        "library",
+				RpsRaw:      20,
+				DurationRaw: time.Second,
+				ThreadsRaw:  2,
+				HTTPRequest: bro.HTTPRequest{URL: url},
+				Checks:      []*bro.Check{{Type: "httpCode", Equals: "200"}},
+				Thresholds:  []*bro.Threshold{{Metric: "checks", Type: "httpCode", MinRate: &minRate}},
+			},
+		},
+	}
+}
+
+func TestRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	listener := &countingListener{}
+
+	result, err := bro.Run(context.Background(), newConfig(server.URL, 1.0), listener)
+	if err != nil {
+		t.Fatalf("failed to run: %v", err)
+	}
+
+	bro.RequireThresholds(t, result)
+
+	if len(result.Scenarios) != 1 || result.Scenarios[0].Success == 0 {
+		t.Fatalf("unexpected result: %+v", result.Scenarios)
+	}
+
+	if listener.responses.Load() != result.Scenarios[0].Total || listener.failed.Load() != 0 {
+		t.Errorf("listener tracked %d responses; expected %d", listener.responses.Load(), result.Scenarios[0].Total)
+	}
+}
+
+func TestRequireThresholds(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	result, err := bro.Run(context.Background(), newConfig(server.URL, 1.0))
+	if err != nil {
+		t.Fatalf("failed to run: %v", err)
+	}
+
+	fake := &fakeT{}
+	bro.RequireThresholds(fake, result)
+
+	if !fake.failed || result.Success || result.Scenarios[0].Passed {
+		t.Errorf("expected failed thresholds: %+v", result.Scenarios[0])
+	}
+}
+
+func TestRun_InvalidScenario(t *testing.T) {
+	conf := &bro.Config{
+		Name:      "invalid",
+		Scenarios: []*bro.Scenario{{Name: "unknown", TypeRaw: "unknown"}},
+	}
+
+	result, err := bro.Run(context.Background(), conf)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+
+	if result.Success {
+		t.Errorf("expected failed result")
+	}
+
+	fake := &fakeT{}
+	bro.RequireThresholds(fake, result)
+
+	if !fake.failed || !strings.Contains(fake.message, "unknown") {
+		t.Errorf("expected missing scenario, got %q", fake.message)
+	}
+}
+
+func TestRun_Parallel(t *testing.T) {
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ok.Close()
+
+	notFound := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer notFound.Close()
+
+	// runs with the same scenario name count their checks separately
+	results := make([]*bro.Result, 2)
+
+	var wg sync.WaitGroup
+
+	for i, url := range []string{ok.URL, notFound.URL} {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			results[i], _ = bro.Run(context.Background(), newConfig(url, 1.0))
+		}()
+	}
+
+	wg.Wait()
+
+	if !results[0].Success || results[1].Success {
+		t.Errorf("unexpected results: %v and %v", results[0].Success, results[1].Success)
+	}
+}
@@ -0,0 +1,37 @@
+package bro
+
+import (
+	"github.com/lameaux/bro/internal/client/config"
+)
+
+// Config types are the same as in YAML files, see docs/format.md.
+type (
+	Config           = config.Config
+	Scenario         = config.Scenario
+	Stage            = config.Stage
+	Check            = config.Check
+	Extractor        = config.Extractor
+	Threshold        = config.Threshold
+	Percent          = config.Percent
+	HTTPClient       = config.HTTPClient
+	HTTP2            = config.HTTP2
+	TLS              = config.TLS
+	Proxy            = config.Proxy
+	DNS              = config.DNS
+	HTTPRequest      = config.HTTPRequest
+	MultipartField   = config.MultipartField
+	Stream           = config.Stream
+	Script           = config.Script
+	GRPCRequest      = config.GRPCRequest
+	GraphQLRequest   = config.GraphQLRequest
+	WebSocket        = config.WebSocket
+	WebSocketMessage = config.WebSocketMessage
+	SocketRequest    = config.SocketRequest
+	SocketResponse   = config.SocketResponse
+	DNSRequest       = config.DNSRequest
+)
+
+// LoadConfig reads a YAML file and applies defaults to its scenarios.
+func LoadConfig(fileName string) (*Config, error) {
+	return config.Load(fileName) //nolint:wrapcheck
+}
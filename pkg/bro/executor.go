@@ -0,0 +1,30 @@
+package bro
+
+import (
+	"github.com/lameaux/bro/internal/client/checker"
+	"github.com/lameaux/bro/internal/client/runner"
+	"github.com/lameaux/bro/internal/client/tracking"
+)
+
+// Executor sends requests of a custom scenario type, see RegisterExecutor.
+// It implements RequestExecutor or SessionExecutor.
+type (
+	Executor        = runner.Executor
+	RequestExecutor = runner.RequestExecutor
+	SessionExecutor = runner.SessionExecutor
+	ExecutorFactory = runner.ExecutorFactory
+	HTTPClients     = runner.HTTPClients
+	Labels          = runner.Labels
+	Request         = runner.Request
+	Response        = runner.Response
+	Tracker         = runner.Tracker
+	CheckResult     = checker.Result
+	Timings         = tracking.Timings
+	Bytes           = tracking.Bytes
+	StreamInfo      = tracking.StreamInfo
+)
+
+// RegisterExecutor adds an executor for scenarios with the type, its settings are Scenario.Params.
+func RegisterExecutor(scenarioType string, factory ExecutorFactory) {
+	runner.RegisterExecutor(scenarioType, factory)
+}
@@ -0,0 +1,19 @@
+package bro
+
+import (
+	"github.com/lameaux/bro/internal/client/runner"
+	"github.com/lameaux/bro/internal/client/tracking"
+)
+
+// StatListener is notified about every response and failed request of all scenarios.
+// It may also implement StageListener, RequestListener or ConnectionListener.
+type (
+	StatListener       = runner.StatListener
+	StageListener      = runner.StageListener
+	RequestListener    = runner.RequestListener
+	ConnectionListener = runner.ConnectionListener
+
+	RequestInfo    = tracking.RequestInfo
+	StageInfo      = tracking.StageInfo
+	ConnectionInfo = tracking.ConnectionInfo
+)
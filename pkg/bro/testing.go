@@ -0,0 +1,37 @@
+package bro
+
+import (
+	"strings"
+	"testing"
+)
+
+// RequireThresholds fails a test if thresholds of any scenario failed, or any scenario has no result.
+func RequireThresholds(t testing.TB, result *Result) {
+	t.Helper()
+
+	if result == nil {
+		t.Fatalf("missing result, run failed to start")
+
+		return
+	}
+
+	if result.Success {
+		return
+	}
+
+	if len(result.Missing) > 0 {
+		t.Fatalf("scenarios of %s failed to run: [%s]", result.Name, strings.Join(result.Missing, ", "))
+
+		return
+	}
+
+	var failed []string
+
+	for _, scenario := range result.Scenarios {
+		if !scenario.Passed {
+			failed = append(failed, scenario.Name)
+		}
+	}
+
+	t.Fatalf("thresholds failed in %s: [%s]", result.Name, strings.Join(failed, ", "))
+}
@@ -25,16 +25,22 @@ type MetricV1 struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Instance       string  `protobuf:"bytes,1,opt,name=instance,proto3" json:"instance,omitempty"`
-	Group          string  `protobuf:"bytes,2,opt,name=group,proto3" json:"group,omitempty"`
-	Scenario       string  `protobuf:"bytes,3,opt,name=scenario,proto3" json:"scenario,omitempty"`
-	Method         string  `protobuf:"bytes,4,opt,name=method,proto3" json:"method,omitempty"`
-	Url            string  `protobuf:"bytes,5,opt,name=url,proto3" json:"url,omitempty"`
-	Code           string  `protobuf:"bytes,6,opt,name=code,proto3" json:"code,omitempty"`
-	Failed         bool    `protobuf:"varint,7,opt,name=failed,proto3" json:"failed,omitempty"`
-	Timeout        bool    `protobuf:"varint,8,opt,name=timeout,proto3" json:"timeout,omitempty"`
-	Success        bool    `protobuf:"varint,9,opt,name=success,proto3" json:"success,omitempty"`
-	LatencySeconds float64 `protobuf:"fixed64,10,opt,name=latencySeconds,proto3" json:"latencySeconds,omitempty"`
+	Instance             string             `protobuf:"bytes,1,opt,name=instance,proto3" json:"instance,omitempty"`
+	Group                string             `protobuf:"bytes,2,opt,name=group,proto3" json:"group,omitempty"`
+	Scenario             string             `protobuf:"bytes,3,opt,name=scenario,proto3" json:"scenario,omitempty"`
+	Method               string             `protobuf:"bytes,4,opt,name=method,proto3" json:"method,omitempty"`
+	Url                  string             `protobuf:"bytes,5,opt,name=url,proto3" json:"url,omitempty"`
+	Code                 string             `protobuf:"bytes,6,opt,name=code,proto3" json:"code,omitempty"`
+	Failed               bool               `protobuf:"varint,7,opt,name=failed,proto3" json:"failed,omitempty"`
+	Timeout              bool               `protobuf:"varint,8,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	Success              bool               `protobuf:"varint,9,opt,name=success,proto3" json:"success,omitempty"`
+	Error                string             `protobuf:"bytes,13,opt,name=error,proto3" json:"error,omitempty"`
+	LatencySeconds       float64            `protobuf:"fixed64,10,opt,name=latencySeconds,proto3" json:"latencySeconds,omitempty"`
+	TimingSeconds        map[string]float64 `protobuf:"bytes,11,rep,name=timingSeconds,proto3" json:"timingSeconds,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+	ConnReused           bool               `protobuf:"varint,12,opt,name=connReused,proto3" json:"connReused,omitempty"`
+	BytesSent            int64              `protobuf:"varint,14,opt,name=bytesSent,proto3" json:"bytesSent,omitempty"`
+	BytesReceived        int64              `protobuf:"varint,15,opt,name=bytesReceived,proto3" json:"bytesReceived,omitempty"`
+	BytesReceivedDecoded int64              `protobuf:"varint,16,opt,name=bytesReceivedDecoded,proto3" json:"bytesReceivedDecoded,omitempty"`
 }
 
 func (x *MetricV1) Reset() {
@@ -132,6 +138,13 @@ func (x *MetricV1) GetSuccess() bool {
 	return false
 }
 
+func (x *MetricV1) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
 func (x *MetricV1) GetLatencySeconds() float64 {
 	if x != nil {
 		return x.LatencySeconds
@@ -139,6 +152,41 @@ func (x *MetricV1) GetLatencySeconds() float64 {
 	return 0
 }
 
+func (x *MetricV1) GetTimingSeconds() map[string]float64 {
+	if x != nil {
+		return x.TimingSeconds
+	}
+	return nil
+}
+
+func (x *MetricV1) GetConnReused() bool {
+	if x != nil {
+		return x.ConnReused
+	}
+	return false
+}
+
+func (x *MetricV1) GetBytesSent() int64 {
+	if x != nil {
+		return x.BytesSent
+	}
+	return 0
+}
+
+func (x *MetricV1) GetBytesReceived() int64 {
+	if x != nil {
+		return x.BytesReceived
+	}
+	return 0
+}
+
+func (x *MetricV1) GetBytesReceivedDecoded() int64 {
+	if x != nil {
+		return x.BytesReceivedDecoded
+	}
+	return 0
+}
+
 type Empty struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -182,7 +230,7 @@ var File_protos_metrics_metrics_proto protoreflect.FileDescriptor
 var file_protos_metrics_metrics_proto_rawDesc = []byte{
 	0x0a, 0x1c, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73,
 	0x2f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x07,
-	0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x22, 0x8a, 0x02, 0x0a, 0x08, 0x4d, 0x65, 0x74, 0x72,
+	0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x22, 0xc6, 0x04, 0x0a, 0x08, 0x4d, 0x65, 0x74, 0x72,
 	0x69, 0x63, 0x56, 0x31, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
 	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
 	0x12, 0x14, 0x0a, 0x05, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
@@ -196,17 +244,37 @@ var file_protos_metrics_metrics_proto_rawDesc = []byte{
 	0x52, 0x06, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65,
 	0x6f, 0x75, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f,
 	0x75, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x09, 0x20,
-	0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x26, 0x0a, 0x0e,
-	0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x0a,
-	0x20, 0x01, 0x28, 0x01, 0x52, 0x0e, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x53, 0x65, 0x63,
-	0x6f, 0x6e, 0x64, 0x73, 0x22, 0x07, 0x0a, 0x05, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x32, 0x3a, 0x0a,
-	0x09, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x56, 0x31, 0x12, 0x2d, 0x0a, 0x04, 0x73, 0x65,
-	0x6e, 0x64, 0x12, 0x11, 0x2e, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x2e, 0x4d, 0x65, 0x74,
-	0x72, 0x69, 0x63, 0x56, 0x31, 0x1a, 0x0e, 0x2e, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x2e,
-	0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x28, 0x01, 0x42, 0x27, 0x5a, 0x25, 0x67, 0x69, 0x74,
-	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6c, 0x61, 0x6d, 0x65, 0x61, 0x75, 0x78, 0x2f,
-	0x62, 0x72, 0x6f, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2f, 0x6d, 0x65, 0x74, 0x72, 0x69,
-	0x63, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x14, 0x0a, 0x05,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x12, 0x26, 0x0a, 0x0e, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x53, 0x65, 0x63,
+	0x6f, 0x6e, 0x64, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0e, 0x6c, 0x61, 0x74, 0x65,
+	0x6e, 0x63, 0x79, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x12, 0x4a, 0x0a, 0x0d, 0x74, 0x69,
+	0x6d, 0x69, 0x6e, 0x67, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x24, 0x2e, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x2e, 0x4d, 0x65, 0x74, 0x72,
+	0x69, 0x63, 0x56, 0x31, 0x2e, 0x54, 0x69, 0x6d, 0x69, 0x6e, 0x67, 0x53, 0x65, 0x63, 0x6f, 0x6e,
+	0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0d, 0x74, 0x69, 0x6d, 0x69, 0x6e, 0x67, 0x53,
+	0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x6e, 0x52, 0x65,
+	0x75, 0x73, 0x65, 0x64, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x6e,
+	0x52, 0x65, 0x75, 0x73, 0x65, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x62, 0x79, 0x74, 0x65, 0x73, 0x53,
+	0x65, 0x6e, 0x74, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x62, 0x79, 0x74, 0x65, 0x73,
+	0x53, 0x65, 0x6e, 0x74, 0x12, 0x24, 0x0a, 0x0d, 0x62, 0x79, 0x74, 0x65, 0x73, 0x52, 0x65, 0x63,
+	0x65, 0x69, 0x76, 0x65, 0x64, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x62, 0x79, 0x74,
+	0x65, 0x73, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x12, 0x32, 0x0a, 0x14, 0x62, 0x79,
+	0x74, 0x65, 0x73, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x44, 0x65, 0x63, 0x6f, 0x64,
+	0x65, 0x64, 0x18, 0x10, 0x20, 0x01, 0x28, 0x03, 0x52, 0x14, 0x62, 0x79, 0x74, 0x65, 0x73, 0x52,
+	0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x44, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x1a, 0x40,
+	0x0a, 0x12, 0x54, 0x69, 0x6d, 0x69, 0x6e, 0x67, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
+	0x22, 0x07, 0x0a, 0x05, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x32, 0x3a, 0x0a, 0x09, 0x4d, 0x65, 0x74,
+	0x72, 0x69, 0x63, 0x73, 0x56, 0x31, 0x12, 0x2d, 0x0a, 0x04, 0x73, 0x65, 0x6e, 0x64, 0x12, 0x11,
+	0x2e, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x2e, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x56,
+	0x31, 0x1a, 0x0e, 0x2e, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x2e, 0x45, 0x6d, 0x70, 0x74,
+	0x79, 0x22, 0x00, 0x28, 0x01, 0x42, 0x27, 0x5a, 0x25, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
+	0x63, 0x6f, 0x6d, 0x2f, 0x6c, 0x61, 0x6d, 0x65, 0x61, 0x75, 0x78, 0x2f, 0x62, 0x72, 0x6f, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -221,19 +289,21 @@ func file_protos_metrics_metrics_proto_rawDescGZIP() []byte {
 	return file_protos_metrics_metrics_proto_rawDescData
 }
 
-var file_protos_metrics_metrics_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_protos_metrics_metrics_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
 var file_protos_metrics_metrics_proto_goTypes = []any{
 	(*MetricV1)(nil), // 0: metrics.MetricV1
 	(*Empty)(nil),    // 1: metrics.Empty
+	nil,              // 2: metrics.MetricV1.TimingSecondsEntry
 }
 var file_protos_metrics_metrics_proto_depIdxs = []int32{
-	0, // 0: metrics.MetricsV1.send:input_type -> metrics.MetricV1
-	1, // 1: metrics.MetricsV1.send:output_type -> metrics.Empty
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	2, // 0: metrics.MetricV1.timingSeconds:type_name -> metrics.MetricV1.TimingSecondsEntry
+	0, // 1: metrics.MetricsV1.send:input_type -> metrics.MetricV1
+	1, // 2: metrics.MetricsV1.send:output_type -> metrics.Empty
+	2, // [2:3] is the sub-list for method output_type
+	1, // [1:2] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_protos_metrics_metrics_proto_init() }
@@ -273,7 +343,7 @@ func file_protos_metrics_metrics_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_protos_metrics_metrics_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   3,
 			NumExtensions: 0,
 			NumServices:   1,
 		},